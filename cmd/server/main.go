@@ -9,13 +9,18 @@ import (
 	"syscall"
 	"time"
 
+	"fiozap/internal/api/auth"
 	"fiozap/internal/api/router"
+	"fiozap/internal/audit"
 	"fiozap/internal/config"
 	"fiozap/internal/database"
 	"fiozap/internal/integrations/webhook"
 	"fiozap/internal/logger"
+	"fiozap/internal/metrics"
 	"fiozap/internal/providers/wameow"
+	"fiozap/internal/ratelimit"
 	"fiozap/internal/repository"
+	"fiozap/internal/tracing"
 
 	_ "fiozap/docs"
 )
@@ -51,14 +56,52 @@ func main() {
 	defer func() { _ = db.Close() }()
 	log.Info().Msg("Connected to database")
 
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Enabled:     cfg.TracingEnabled,
+		ServiceName: cfg.TracingServiceName,
+		InstanceID:  cfg.InstanceID,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to setup tracing")
+	}
+	if cfg.TracingEnabled {
+		log.Info().Msg("OpenTelemetry tracing enabled")
+	}
+
+	limiter, err := newRateLimiter(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to setup rate limiting")
+	}
+	if limiter != nil {
+		log.Info().Str("store", cfg.RateLimitStore).Msg("Rate limiting enabled")
+	}
+
+	trustedProxies, err := auth.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid TRUSTED_PROXIES")
+	}
+
 	repos := repository.New(db.DB)
 	webhookDispatcher := webhook.NewDispatcher(log)
-	provider := wameow.New(db.Container, repos.Session, log, webhookDispatcher)
+	auditWriter := audit.NewWriter(repos.Audit, log)
+	provider := wameow.New(db.Container, repos, log, webhookDispatcher, wameow.Options{
+		ReconnectConcurrency: cfg.ReconnectConcurrency,
+		ReconnectBaseDelay:   cfg.ReconnectBaseDelay,
+		ReconnectMaxDelay:    cfg.ReconnectMaxDelay,
+		DeviceName:           cfg.DeviceName,
+		DevicePlatform:       cfg.DevicePlatform,
+		InstanceID:           cfg.InstanceID,
+		InstanceAddr:         cfg.InstanceAddr,
+		LeaseTTL:             cfg.LeaseTTL,
+		LeaseRenewInterval:   cfg.LeaseRenewInterval,
+		IdleWakeTimeout:      cfg.IdleWakeTimeout,
+	})
+	metrics.Registry.MustRegister(provider.Collector())
 
 	addr := fmt.Sprintf("%s:%s", cfg.ServerHost, cfg.ServerPort)
 	server := &http.Server{
 		Addr:    addr,
-		Handler: router.New(provider, log, cfg.GlobalAPIToken, webhookDispatcher),
+		Handler: router.New(provider, repos, db, log, cfg.GlobalAPIToken, cfg.MetricsToken, webhookDispatcher, limiter, trustedProxies, auditWriter),
 	}
 
 	go func() {
@@ -74,12 +117,80 @@ func main() {
 
 	log.Info().Msg("Received shutdown signal")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer cancel()
 
-	if err := server.Shutdown(ctx); err != nil {
+	// 1. Para de aceitar requisicoes e aguarda as em andamento (streams longos nao seguram o resto)
+	httpCtx, httpCancel := context.WithTimeout(ctx, cfg.ShutdownTimeout/3)
+	defer httpCancel()
+	if err := server.Shutdown(httpCtx); err != nil {
 		log.Error().Err(err).Msg("Failed to gracefully shutdown server")
 	}
 
+	// 2. Grava os registros de auditoria pendentes
+	if err := auditWriter.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to drain audit entries")
+	}
+
+	// 3. Desconecta as sessoes e grava o estado para reconectar no proximo boot
+	if err := provider.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to gracefully shutdown sessions")
+	}
+
+	// 4. Entrega os webhooks pendentes (inclusive os de desconexao) ate o prazo
+	if err := webhookDispatcher.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to drain webhooks")
+	}
+
+	// 5. Fecha a conexao do rate limiting (Redis)
+	if err := limiter.Close(); err != nil {
+		log.Error().Err(err).Msg("Failed to close rate limit store")
+	}
+
+	// 6. Exporta os spans pendentes
+	if err := shutdownTracing(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to flush traces")
+	}
+
 	log.Info().Msg("Server stopped")
 }
+
+// newRateLimiter monta os limites configurados sobre o armazenamento escolhido (nil se desativado)
+func newRateLimiter(cfg *config.Config) (*ratelimit.Limiter, error) {
+	if !cfg.RateLimitEnabled {
+		return nil, nil
+	}
+
+	specs := map[string]string{
+		ratelimit.ScopeToken:        cfg.RateLimitToken,
+		ratelimit.ScopeSession:      cfg.RateLimitSession,
+		ratelimit.ClassSend:         cfg.RateLimitSend,
+		ratelimit.ClassContactCheck: cfg.RateLimitContactCheck,
+		ratelimit.ClassGroupWrite:   cfg.RateLimitGroupWrite,
+	}
+	limits := make(map[string]ratelimit.Limit, len(specs))
+	for scope, spec := range specs {
+		limit, err := ratelimit.ParseLimit(spec)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", scope, err)
+		}
+		limits[scope] = limit
+	}
+
+	var store ratelimit.Store
+	switch cfg.RateLimitStore {
+	case "memory":
+		store = ratelimit.NewMemoryStore()
+	case "redis":
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		redisStore, err := ratelimit.NewRedisStore(ctx, cfg.RedisURL)
+		if err != nil {
+			return nil, err
+		}
+		store = redisStore
+	default:
+		return nil, fmt.Errorf("invalid RATE_LIMIT_STORE %q: use memory or redis", cfg.RateLimitStore)
+	}
+	return ratelimit.New(store, limits), nil
+}
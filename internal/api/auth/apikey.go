@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"fiozap/internal/repository"
+)
+
+// APIKeyPrefix identifica API keys e evita consulta ao banco para tokens de sessao
+const APIKeyPrefix = "fzk_"
+
+// GenerateAPIKey retorna a chave em texto puro, o prefixo exibivel e o hash guardado no banco
+func GenerateAPIKey() (key, prefix, hash string) {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	key = APIKeyPrefix + hex.EncodeToString(b)
+	return key, key[:len(APIKeyPrefix)+8], HashAPIKey(key)
+}
+
+// HashAPIKey retorna o SHA-256 da chave em hex
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidIPRule aceita IP ou CIDR
+func ValidIPRule(rule string) bool {
+	if _, _, err := net.ParseCIDR(rule); err == nil {
+		return true
+	}
+	return net.ParseIP(rule) != nil
+}
+
+// resolveKey valida a API key; retorna nil com status e mensagem de erro quando recusada
+func (a *Auth) resolveKey(r *http.Request, token string) (*repository.APIKeyModel, int, string) {
+	if !strings.HasPrefix(token, APIKeyPrefix) {
+		return nil, http.StatusUnauthorized, "invalid token"
+	}
+
+	key, err := a.apiKeys.GetByHash(r.Context(), HashAPIKey(token))
+	if err != nil {
+		a.log.Error().Err(err).Msg("Failed to load api key")
+		return nil, http.StatusInternalServerError, "failed to validate api key"
+	}
+	if key == nil {
+		return nil, http.StatusUnauthorized, "invalid token"
+	}
+	if key.ExpiresAt.Valid && time.Now().After(key.ExpiresAt.Time) {
+		return nil, http.StatusUnauthorized, "api key expired"
+	}
+	if !allowsIP(key, ClientIP(r)) {
+		return nil, http.StatusForbidden, "api key not allowed from this address"
+	}
+
+	go func(id string) {
+		if err := a.apiKeys.TouchLastUsed(context.Background(), id); err != nil {
+			a.log.Warn().Err(err).Str("key", key.Prefix).Msg("Failed to update api key last use")
+		}
+	}(key.ID)
+
+	return key, 0, ""
+}
+
+func allowsSession(key *repository.APIKeyModel, name string) bool {
+	if len(key.Sessions) == 0 {
+		return true
+	}
+	for _, s := range key.Sessions {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+func allowsIP(key *repository.APIKeyModel, ip net.IP) bool {
+	if len(key.AllowedIPs) == 0 {
+		return true
+	}
+	if ip == nil {
+		return false
+	}
+	for _, rule := range key.AllowedIPs {
+		if _, network, err := net.ParseCIDR(rule); err == nil {
+			if network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if allowed := net.ParseIP(rule); allowed != nil && allowed.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
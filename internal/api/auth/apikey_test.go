@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	"fiozap/internal/repository"
+)
+
+func TestAllowsIP(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		ip      string
+		want    bool
+	}{
+		{"no rules", nil, "203.0.113.7", true},
+		{"exact match", []string{"203.0.113.7"}, "203.0.113.7", true},
+		{"exact mismatch", []string{"203.0.113.7"}, "203.0.113.8", false},
+		{"cidr match", []string{"10.0.0.0/8"}, "10.1.2.3", true},
+		{"cidr mismatch", []string{"10.0.0.0/8"}, "192.168.0.1", false},
+		{"ipv6 cidr", []string{"2001:db8::/32"}, "2001:db8::1", true},
+		{"unknown ip with rules", []string{"10.0.0.0/8"}, "", false},
+		{"invalid rule ignored", []string{"bogus", "10.0.0.1"}, "10.0.0.1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := &repository.APIKeyModel{AllowedIPs: tt.allowed}
+			if got := allowsIP(key, net.ParseIP(tt.ip)); got != tt.want {
+				t.Errorf("allowsIP(%v, %q) = %v, want %v", tt.allowed, tt.ip, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveClientIP(t *testing.T) {
+	trusted, err := ParseTrustedProxies("10.0.0.0/8, 192.168.1.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		trusted []*net.IPNet
+		remote  string
+		headers map[string]string
+		want    string
+	}{
+		{"peer without proxies", nil, "203.0.113.7:5000", nil, "203.0.113.7"},
+		{"headers ignored without proxies", nil, "203.0.113.7:5000", map[string]string{"X-Forwarded-For": "1.2.3.4", "X-Real-IP": "1.2.3.4"}, "203.0.113.7"},
+		{"headers ignored from untrusted peer", trusted, "203.0.113.7:5000", map[string]string{"X-Forwarded-For": "1.2.3.4"}, "203.0.113.7"},
+		{"forwarded for from trusted peer", trusted, "10.0.0.5:5000", map[string]string{"X-Forwarded-For": "1.2.3.4"}, "1.2.3.4"},
+		{"spoofed hop before real client", trusted, "10.0.0.5:5000", map[string]string{"X-Forwarded-For": "6.6.6.6, 1.2.3.4, 10.0.0.9"}, "1.2.3.4"},
+		{"single trusted ip", trusted, "192.168.1.1:5000", map[string]string{"X-Real-IP": "1.2.3.4"}, "1.2.3.4"},
+		{"trusted peer without headers", trusted, "10.0.0.5:5000", nil, "10.0.0.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remote
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			if got := resolveClientIP(r, tt.trusted); got.String() != tt.want {
+				t.Errorf("resolveClientIP() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxiesInvalid(t *testing.T) {
+	if _, err := ParseTrustedProxies("10.0.0.0/8,not-an-ip"); err == nil {
+		t.Error("expected error for invalid entry")
+	}
+}
@@ -2,31 +2,107 @@ package auth
 
 import (
 	"context"
+	"crypto/subtle"
 	"net/http"
+	"strings"
 
 	"fiozap/internal/api/dto"
 	"fiozap/internal/core"
+	"fiozap/internal/repository"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
 )
 
 type ctxKey string
 
-const CtxKeyIsGlobal ctxKey = "isGlobal"
+const (
+	CtxKeyIsGlobal  ctxKey = "isGlobal"
+	CtxKeyPrincipal ctxKey = "principal"
+
+	ctxKeyPrincipalSlot ctxKey = "principalSlot"
+)
+
+// Principal identifica quem fez a requisicao
+type Principal struct {
+	Global  bool                    // token global
+	Session string                  // token da sessao (nome da sessao)
+	Key     *repository.APIKeyModel // API key com escopos
+	Tenant  *repository.TenantModel // token de admin do tenant (apenas sessoes do tenant)
+}
+
+// HasScope tokens global, de tenant e de sessao tem todos os escopos; API keys so os concedidos
+func (p *Principal) HasScope(scope string) bool {
+	if p.Key == nil {
+		return true
+	}
+	for _, s := range p.Key.Scopes {
+		if s == ScopeAll || s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Actor identifica o autor para a auditoria: tipo e id do token (id do tenant ou da API key, nome da sessao)
+func (p *Principal) Actor() (kind, id string) {
+	switch {
+	case p == nil:
+		return "anonymous", ""
+	case p.Global:
+		return "global", ""
+	case p.Tenant != nil:
+		return "tenant", p.Tenant.ID
+	case p.Key != nil:
+		return "apikey", p.Key.ID
+	default:
+		return "session", p.Session
+	}
+}
+
+// AllowsSession indica se o principal pode usar a sessao pelo nome; so API keys tem allowlist
+// (token de tenant e isolado pelo tenant da sessao)
+func (p *Principal) AllowsSession(name string) bool {
+	return p.Key == nil || allowsSession(p.Key, name)
+}
+
+// PrincipalFromContext retorna o principal autenticado (nil se a rota nao tem auth)
+func PrincipalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(CtxKeyPrincipal).(*Principal)
+	return p
+}
+
+// principalSlot guarda o principal autenticado para middlewares externos a autenticacao
+type principalSlot struct {
+	p *Principal
+}
+
+// TrackPrincipal permite ler, depois do handler, o principal autenticado mais adiante na cadeia
+// (o contexto com o principal nao volta para os middlewares anteriores)
+func TrackPrincipal(ctx context.Context) (context.Context, func() *Principal) {
+	slot := &principalSlot{}
+	return context.WithValue(ctx, ctxKeyPrincipalSlot, slot), func() *Principal { return slot.p }
+}
 
 type Auth struct {
 	globalToken string
 	provider    core.Provider
+	apiKeys     repository.APIKeyRepository
+	tenants     repository.TenantRepository
+	log         zerolog.Logger
 }
 
-func NewAuth(globalToken string, provider core.Provider) *Auth {
+func NewAuth(globalToken string, provider core.Provider, repos *repository.Repositories, log zerolog.Logger) *Auth {
 	return &Auth{
 		globalToken: globalToken,
 		provider:    provider,
+		apiKeys:     repos.APIKey,
+		tenants:     repos.Tenant,
+		log:         log.With().Str("component", "auth").Logger(),
 	}
 }
 
-// Global verifica apenas o token global (para rotas admin como criar sessao)
+// Global verifica apenas o token global (para rotas admin como gerenciar API keys)
 func (a *Auth) Global(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		token := r.Header.Get("Authorization")
@@ -35,17 +111,50 @@ func (a *Auth) Global(next http.Handler) http.Handler {
 			return
 		}
 
-		if a.globalToken == "" || token != a.globalToken {
+		if !a.isGlobal(token) {
 			dto.Error(w, http.StatusUnauthorized, "invalid token")
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), CtxKeyIsGlobal, true)
-		next.ServeHTTP(w, r.WithContext(ctx))
+		next.ServeHTTP(w, r.WithContext(withPrincipal(r.Context(), &Principal{Global: true})))
 	})
 }
 
-// Session verifica token global OU token da sessao especifica
+// Admin aceita token global, token de tenant ou API key (rotas fora de uma sessao, como criar sessao)
+func (a *Auth) Admin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("Authorization")
+		if token == "" {
+			dto.Error(w, http.StatusUnauthorized, "missing token")
+			return
+		}
+
+		if a.isGlobal(token) {
+			next.ServeHTTP(w, r.WithContext(withPrincipal(r.Context(), &Principal{Global: true})))
+			return
+		}
+
+		if strings.HasPrefix(token, TenantTokenPrefix) {
+			tenant, status, msg := a.resolveTenant(r, token)
+			if tenant == nil {
+				dto.Error(w, status, msg)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(withPrincipal(r.Context(), &Principal{Tenant: tenant})))
+			return
+		}
+
+		key, status, msg := a.resolveKey(r, token)
+		if key == nil {
+			dto.Error(w, status, msg)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(withPrincipal(r.Context(), &Principal{Key: key})))
+	})
+}
+
+// Session verifica token global, token da sessao especifica OU API key com acesso a sessao
 func (a *Auth) Session(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		token := r.Header.Get("Authorization")
@@ -55,9 +164,8 @@ func (a *Auth) Session(next http.Handler) http.Handler {
 		}
 
 		// Token global tem acesso a tudo
-		if a.globalToken != "" && token == a.globalToken {
-			ctx := context.WithValue(r.Context(), CtxKeyIsGlobal, true)
-			next.ServeHTTP(w, r.WithContext(ctx))
+		if a.isGlobal(token) {
+			next.ServeHTTP(w, r.WithContext(withPrincipal(r.Context(), &Principal{Global: true})))
 			return
 		}
 
@@ -68,18 +176,89 @@ func (a *Auth) Session(next http.Handler) http.Handler {
 			return
 		}
 
+		if strings.HasPrefix(token, APIKeyPrefix) {
+			key, status, msg := a.resolveKey(r, token)
+			if key == nil {
+				dto.Error(w, status, msg)
+				return
+			}
+			if !allowsSession(key, name) {
+				dto.Error(w, http.StatusForbidden, "api key not allowed for this session")
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(withPrincipal(r.Context(), &Principal{Key: key})))
+			return
+		}
+
 		session, err := a.provider.GetSession(name)
 		if err != nil {
 			dto.Error(w, http.StatusNotFound, "session not found")
 			return
 		}
 
-		if session.GetToken() != token {
+		// Admin do tenant acessa apenas as sessoes do proprio tenant
+		if strings.HasPrefix(token, TenantTokenPrefix) {
+			tenant, status, msg := a.resolveTenant(r, token)
+			if tenant == nil {
+				dto.Error(w, status, msg)
+				return
+			}
+			if session.GetTenantID() != tenant.ID {
+				dto.Error(w, http.StatusNotFound, "session not found")
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(withPrincipal(r.Context(), &Principal{Tenant: tenant})))
+			return
+		}
+
+		if !session.CheckToken(token) {
 			dto.Error(w, http.StatusUnauthorized, "invalid token")
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), CtxKeyIsGlobal, false)
-		next.ServeHTTP(w, r.WithContext(ctx))
+		next.ServeHTTP(w, r.WithContext(withPrincipal(r.Context(), &Principal{Session: name})))
 	})
 }
+
+// GlobalOnly exige o token global; usar depois de Session em rotas sensiveis de uma sessao
+func (a *Auth) GlobalOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p := PrincipalFromContext(r.Context())
+		if p == nil || !p.Global {
+			dto.Error(w, http.StatusForbidden, "global token required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Require exige o escopo informado; usar depois de Global, Admin ou Session
+func (a *Auth) Require(scope string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p := PrincipalFromContext(r.Context())
+			if p == nil {
+				dto.Error(w, http.StatusUnauthorized, "missing token")
+				return
+			}
+			if !p.HasScope(scope) {
+				dto.Error(w, http.StatusForbidden, "missing scope "+scope)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isGlobal compara com o token global em tempo constante
+func (a *Auth) isGlobal(token string) bool {
+	return a.globalToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(a.globalToken)) == 1
+}
+
+func withPrincipal(ctx context.Context, p *Principal) context.Context {
+	if slot, ok := ctx.Value(ctxKeyPrincipalSlot).(*principalSlot); ok {
+		slot.p = p
+	}
+	ctx = context.WithValue(ctx, CtxKeyIsGlobal, p.Global)
+	return context.WithValue(ctx, CtxKeyPrincipal, p)
+}
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type clientIPCtxKey struct{}
+
+// ParseTrustedProxies le a lista de proxies confiaveis (IPs ou CIDRs separados por virgula)
+func ParseTrustedProxies(s string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, rule := range strings.Split(s, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		if !strings.Contains(rule, "/") {
+			ip := net.ParseIP(rule)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", rule)
+			}
+			bits := 128
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(rule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", rule)
+		}
+		nets = append(nets, network)
+	}
+	return nets, nil
+}
+
+// RealIP substitui middleware.RealIP: guarda no contexto o IP do cliente e ajusta RemoteAddr.
+// Headers de encaminhamento (X-Forwarded-For, X-Real-IP) so valem quando o par TCP e um proxy
+// confiavel; sem proxies configurados vale sempre o endereco do par
+func RealIP(trusted []*net.IPNet) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := resolveClientIP(r, trusted)
+			if ip != nil {
+				r.RemoteAddr = ip.String()
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientIPCtxKey{}, ip)))
+		})
+	}
+}
+
+// ClientIP retorna o IP do cliente resolvido por RealIP (nil se indisponivel)
+func ClientIP(r *http.Request) net.IP {
+	ip, _ := r.Context().Value(clientIPCtxKey{}).(net.IP)
+	return ip
+}
+
+func resolveClientIP(r *http.Request, trusted []*net.IPNet) net.IP {
+	peer := peerIP(r.RemoteAddr)
+	if peer == nil || !isTrusted(peer, trusted) {
+		return peer
+	}
+
+	// X-Forwarded-For da direita para a esquerda: o primeiro endereco nao confiavel e o cliente
+	if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		hops := strings.Split(strings.Join(xff, ","), ",")
+		var client net.IP
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				break
+			}
+			client = ip
+			if !isTrusted(ip, trusted) {
+				break
+			}
+		}
+		if client != nil {
+			return client
+		}
+	}
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+		return ip
+	}
+	return peer
+}
+
+func peerIP(remoteAddr string) net.IP {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+func isTrusted(ip net.IP, trusted []*net.IPNet) bool {
+	for _, network := range trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
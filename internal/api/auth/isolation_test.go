@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"fiozap/internal/core"
+	"fiozap/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+)
+
+// fakeProvider implementa apenas a busca de sessao usada pelo middleware Session
+type fakeProvider struct {
+	core.Provider
+	sessions map[string]core.Session
+}
+
+func (p *fakeProvider) GetSession(name string) (core.Session, error) {
+	if s, ok := p.sessions[name]; ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("session %s not found", name)
+}
+
+type fakeSession struct {
+	core.Session
+	name   string
+	tenant string
+	token  string
+}
+
+func (s fakeSession) GetName() string              { return s.name }
+func (s fakeSession) GetTenantID() string          { return s.tenant }
+func (s fakeSession) CheckToken(token string) bool { return token == s.token }
+
+// fakeTenants resolve tenants pelo hash do token
+type fakeTenants struct {
+	repository.TenantRepository
+	byHash map[string]*repository.TenantModel
+}
+
+func (f *fakeTenants) GetByTokenHash(_ context.Context, hash string) (*repository.TenantModel, error) {
+	return f.byHash[hash], nil
+}
+
+func TestSessionTenantIsolation(t *testing.T) {
+	const tokenA, tokenB = TenantTokenPrefix + "a", TenantTokenPrefix + "b"
+	tenants := &fakeTenants{byHash: map[string]*repository.TenantModel{
+		HashAPIKey(tokenA): {ID: "tenant-a"},
+		HashAPIKey(tokenB): {ID: "tenant-b"},
+	}}
+	provider := &fakeProvider{sessions: map[string]core.Session{
+		"vendas":  fakeSession{name: "vendas", tenant: "tenant-a", token: "session-token"},
+		"suporte": fakeSession{name: "suporte"},
+	}}
+	a := NewAuth("global", provider, &repository.Repositories{Tenant: tenants}, zerolog.Nop())
+
+	r := chi.NewRouter()
+	r.With(a.Session).Get("/sessions/{name}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name    string
+		session string
+		token   string
+		want    int
+	}{
+		{name: "own tenant", session: "vendas", token: tokenA, want: http.StatusNoContent},
+		{name: "other tenant", session: "vendas", token: tokenB, want: http.StatusNotFound},
+		{name: "tenant on session without tenant", session: "suporte", token: tokenA, want: http.StatusNotFound},
+		{name: "unknown tenant token", session: "vendas", token: TenantTokenPrefix + "x", want: http.StatusUnauthorized},
+		{name: "global", session: "vendas", token: "global", want: http.StatusNoContent},
+		{name: "session token", session: "vendas", token: "session-token", want: http.StatusNoContent},
+		{name: "wrong session token", session: "vendas", token: "other", want: http.StatusUnauthorized},
+		{name: "unknown session", session: "nada", token: tokenA, want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/sessions/"+tt.session, nil)
+			req.Header.Set("Authorization", tt.token)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d (%s)", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package auth
+
+// Escopos de API key. O router exige um escopo por rota
+const (
+	ScopeAll = "*"
+
+	ScopeSessionsAdmin = "sessions:admin" // criar, listar e remover sessoes, rotacionar tokens
+	ScopeSessionsRead  = "sessions:read"  // dados, QR e historico da sessao
+	ScopeSessionsWrite = "sessions:write" // conectar, parear, desconectar, logout
+
+	ScopeMessagesSend = "messages:send"
+	ScopeMessagesRead = "messages:read"
+
+	ScopeContactsRead  = "contacts:read"
+	ScopeContactsWrite = "contacts:write"
+
+	ScopeGroupsRead  = "groups:read"
+	ScopeGroupsWrite = "groups:write"
+
+	ScopeChatsWrite = "chats:write"
+
+	ScopeNewslettersRead  = "newsletters:read"
+	ScopeNewslettersWrite = "newsletters:write"
+
+	ScopePrivacyRead  = "privacy:read"
+	ScopePrivacyWrite = "privacy:write"
+
+	ScopeProfileRead  = "profile:read"
+	ScopeProfileWrite = "profile:write"
+
+	ScopeCallsWrite = "calls:write"
+
+	ScopeWebhookManage = "webhook:manage"
+)
+
+// Scopes retorna todos os escopos aceitos
+func Scopes() []string {
+	return []string{
+		ScopeAll,
+		ScopeSessionsAdmin,
+		ScopeSessionsRead,
+		ScopeSessionsWrite,
+		ScopeMessagesSend,
+		ScopeMessagesRead,
+		ScopeContactsRead,
+		ScopeContactsWrite,
+		ScopeGroupsRead,
+		ScopeGroupsWrite,
+		ScopeChatsWrite,
+		ScopeNewslettersRead,
+		ScopeNewslettersWrite,
+		ScopePrivacyRead,
+		ScopePrivacyWrite,
+		ScopeProfileRead,
+		ScopeProfileWrite,
+		ScopeCallsWrite,
+		ScopeWebhookManage,
+	}
+}
+
+// ValidScope verifica se o escopo existe
+func ValidScope(scope string) bool {
+	for _, s := range Scopes() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"fiozap/internal/api/dto"
+	"fiozap/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// TenantTokenPrefix identifica tokens de admin de tenant
+const TenantTokenPrefix = "fzt_"
+
+// GenerateTenantToken retorna o token em texto puro e o hash guardado no banco
+func GenerateTenantToken() (token, hash string) {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	token = TenantTokenPrefix + hex.EncodeToString(b)
+	return token, HashAPIKey(token)
+}
+
+// resolveTenant valida o token de tenant; retorna nil com status e mensagem quando recusado
+func (a *Auth) resolveTenant(r *http.Request, token string) (*repository.TenantModel, int, string) {
+	tenant, err := a.tenants.GetByTokenHash(r.Context(), HashAPIKey(token))
+	if err != nil {
+		a.log.Error().Err(err).Msg("Failed to load tenant")
+		return nil, http.StatusInternalServerError, "failed to validate tenant token"
+	}
+	if tenant == nil {
+		return nil, http.StatusUnauthorized, "invalid token"
+	}
+	return tenant, 0, ""
+}
+
+// MessageQuota aplica a cota diaria de mensagens do tenant dono da sessao, qualquer que seja o token.
+// A mensagem e reservada antes do envio (requisicoes simultaneas nao passam da cota) e devolvida
+// se a resposta nao for 2xx
+func (a *Auth) MessageQuota(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session, err := a.provider.GetSession(chi.URLParam(r, "name"))
+		if err != nil || session.GetTenantID() == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		tenant, err := a.tenants.Get(r.Context(), session.GetTenantID())
+		if err != nil {
+			a.log.Error().Err(err).Str("tenant", session.GetTenantID()).Msg("Failed to load tenant")
+			dto.Error(w, http.StatusInternalServerError, "failed to check message quota")
+			return
+		}
+		if tenant == nil || tenant.MaxMessagesPerDay <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		day := time.Now().UTC()
+		ok, err := a.tenants.IncrementMessages(r.Context(), tenant.ID, day, tenant.MaxMessagesPerDay)
+		if err != nil {
+			a.log.Error().Err(err).Str("tenant", tenant.ID).Msg("Failed to count message")
+			dto.Error(w, http.StatusInternalServerError, "failed to check message quota")
+			return
+		}
+		if !ok {
+			dto.Error(w, http.StatusTooManyRequests, "daily message quota exceeded")
+			return
+		}
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+		if quotaConsumed(ww.Status()) {
+			return
+		}
+
+		// O contexto da requisicao pode ja ter expirado (timeout do envio)
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), 5*time.Second)
+		defer cancel()
+		if err := a.tenants.ReleaseMessage(ctx, tenant.ID, day); err != nil {
+			a.log.Error().Err(err).Str("tenant", tenant.ID).Msg("Failed to release message quota")
+		}
+	})
+}
+
+// quotaConsumed indica se a resposta conta na cota; sem WriteHeader explicito o status e 200
+func quotaConsumed(status int) bool {
+	return status == 0 || (status >= 200 && status < 300)
+}
@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestQuotaConsumed(t *testing.T) {
+	tests := []struct {
+		status int
+		want   bool
+	}{
+		{status: 0, want: true},
+		{status: http.StatusOK, want: true},
+		{status: http.StatusAccepted, want: true},
+		{status: http.StatusBadRequest, want: false},
+		{status: http.StatusNotFound, want: false},
+		{status: http.StatusTooManyRequests, want: false},
+		{status: http.StatusInternalServerError, want: false},
+		{status: http.StatusServiceUnavailable, want: false},
+	}
+	for _, tt := range tests {
+		if got := quotaConsumed(tt.status); got != tt.want {
+			t.Errorf("quotaConsumed(%d) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package dto
+
+// CreateAPIKeyRequest criacao de API key
+type CreateAPIKeyRequest struct {
+	Name       string   `json:"Name" example:"crm-integration"`
+	Scopes     []string `json:"Scopes" example:"messages:send,groups:read"`
+	Sessions   []string `json:"Sessions,omitempty" example:"sales"`        // vazio = todas as sessoes
+	AllowedIPs []string `json:"AllowedIPs,omitempty" example:"10.0.0.0/8"` // IPs ou CIDRs; vazio = qualquer origem
+	ExpiresAt  int64    `json:"ExpiresAt,omitempty" example:"1735689600"`  // unix, opcional
+}
+
+// APIKeyResponse API key (Key so e exibida na criacao)
+type APIKeyResponse struct {
+	Id         string   `json:"Id"`
+	Name       string   `json:"Name"`
+	Key        string   `json:"Key,omitempty"`
+	Prefix     string   `json:"Prefix" example:"fzk_1a2b3c4d"`
+	Scopes     []string `json:"Scopes"`
+	Sessions   []string `json:"Sessions"`
+	AllowedIPs []string `json:"AllowedIPs"`
+	ExpiresAt  int64    `json:"ExpiresAt,omitempty"`
+	LastUsedAt int64    `json:"LastUsedAt,omitempty"`
+	CreatedAt  int64    `json:"CreatedAt"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+// AuditEntryResponse registro da trilha de auditoria
+type AuditEntryResponse struct {
+	ID        int64  `json:"ID"`
+	ActorType string `json:"ActorType" example:"apikey" enums:"global,tenant,apikey,session,anonymous"`
+	ActorId   string `json:"ActorId,omitempty"` // id do tenant ou da API key, nome da sessao
+	IP        string `json:"IP,omitempty"`
+	RequestId string `json:"RequestId,omitempty"`
+	Method    string `json:"Method" example:"POST"`
+	Route     string `json:"Route" example:"/sessions/{name}/logout"`
+	Path      string `json:"Path" example:"/sessions/vendas/logout"`
+	Session   string `json:"Session,omitempty"`
+	Status    int    `json:"Status" example:"200"`
+	Outcome   string `json:"Outcome" example:"success" enums:"success,failure,denied"`
+	CreatedAt int64  `json:"CreatedAt"`
+}
+
+// AuditListResponse pagina da trilha de auditoria com o total encontrado
+type AuditListResponse struct {
+	Entries []AuditEntryResponse `json:"Entries"`
+	Total   int                  `json:"Total" example:"1200"`
+	Limit   int                  `json:"Limit" example:"100"`
+	Offset  int                  `json:"Offset" example:"0"`
+}
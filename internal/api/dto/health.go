@@ -0,0 +1,23 @@
+package dto
+
+// HealthResponse estado da instancia
+type HealthResponse struct {
+	Status string            `json:"Status" example:"ok" enums:"ok,unavailable"`
+	Checks map[string]string `json:"Checks,omitempty"` // resultado de cada verificacao (ok ou erro)
+}
+
+// SessionHealthResponse diagnostico de conectividade da sessao. Datas em unix (0 = nunca)
+type SessionHealthResponse struct {
+	Name                 string `json:"Name"`
+	Status               string `json:"Status" example:"connected"`
+	Healthy              bool   `json:"Healthy"`
+	Owned                bool   `json:"Owned"` // atendida pela instancia que respondeu
+	WebsocketConnected   bool   `json:"WebsocketConnected"`
+	LoggedIn             bool   `json:"LoggedIn"`
+	LastConnectedAt      int64  `json:"LastConnectedAt"`
+	LastEventAt          int64  `json:"LastEventAt"`
+	LastKeepAliveTimeout int64  `json:"LastKeepAliveTimeout"`
+	UploadedPreKeys      int    `json:"UploadedPreKeys" example:"50"`
+	LastSendError        string `json:"LastSendError,omitempty"`
+	LastSendErrorAt      int64  `json:"LastSendErrorAt,omitempty"`
+}
@@ -12,6 +12,7 @@ type SendImageRequest struct {
 	Image    string `json:"Image" example:"base64..."`
 	Caption  string `json:"Caption,omitempty" example:"Image caption"`
 	MimeType string `json:"Mimetype,omitempty" example:"image/jpeg"`
+	ViewOnce bool   `json:"ViewOnce,omitempty" example:"false"`
 }
 
 // SendVideoRequest request para enviar video
@@ -20,6 +21,7 @@ type SendVideoRequest struct {
 	Video    string `json:"Video" example:"base64..."`
 	Caption  string `json:"Caption,omitempty" example:"Video caption"`
 	MimeType string `json:"Mimetype,omitempty" example:"video/mp4"`
+	ViewOnce bool   `json:"ViewOnce,omitempty" example:"false"`
 }
 
 // SendDocumentRequest request para enviar documento
@@ -36,6 +38,7 @@ type SendAudioRequest struct {
 	Phone    string `json:"Phone" example:"5511999999999"`
 	Audio    string `json:"Audio" example:"base64..."`
 	MimeType string `json:"Mimetype,omitempty" example:"audio/ogg; codecs=opus"`
+	ViewOnce bool   `json:"ViewOnce,omitempty" example:"false"`
 }
 
 // SendStickerRequest request para enviar sticker
@@ -80,11 +83,31 @@ type SendReactionRequest struct {
 type EditMessageRequest struct {
 	Phone string `json:"Phone" example:"5511999999999"`
 	Body  string `json:"Body" example:"Edited message text"`
+	Type  string `json:"Type,omitempty" example:"text" enums:"text,image,video,document"`
 }
 
 // RevokeMessageRequest request para revogar/deletar mensagem
 type RevokeMessageRequest struct {
-	Phone string `json:"Phone" example:"5511999999999"`
+	Phone  string `json:"Phone" example:"5511999999999"`
+	Sender string `json:"Sender,omitempty" example:"5511888888888"`
+}
+
+// PinMessageRequest request para fixar mensagem no chat
+type PinMessageRequest struct {
+	Phone    string `json:"Phone" example:"5511999999999"`
+	Sender   string `json:"Sender,omitempty" example:"5511888888888"`
+	Duration string `json:"Duration,omitempty" example:"7d" enums:"24h,7d,30d"`
+}
+
+// MessageActionRequest request para acoes sobre mensagem existente (unpin, star, keep)
+type MessageActionRequest struct {
+	Phone  string `json:"Phone" example:"5511999999999"`
+	Sender string `json:"Sender,omitempty" example:"5511888888888"`
+}
+
+// MessageActionResponse resposta generica de acoes sobre mensagem
+type MessageActionResponse struct {
+	Details string `json:"Details" example:"Message starred"`
 }
 
 // MessageResponse resposta com ID da mensagem enviada
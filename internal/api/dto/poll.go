@@ -0,0 +1,17 @@
+package dto
+
+// PollResultsResponse contagem atual de votos de uma enquete
+type PollResultsResponse struct {
+	Id          string               `json:"Id" example:"ABCD1234567890"`
+	Chat        string               `json:"Chat" example:"5511999999999@s.whatsapp.net"`
+	Question    string               `json:"Question" example:"What is your favorite color?"`
+	Options     []PollOptionResponse `json:"Options"`
+	TotalVoters int                  `json:"TotalVoters" example:"3"`
+}
+
+// PollOptionResponse votos de uma opcao da enquete
+type PollOptionResponse struct {
+	Name   string   `json:"Name" example:"Blue"`
+	Count  int      `json:"Count" example:"2"`
+	Voters []string `json:"Voters"`
+}
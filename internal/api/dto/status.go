@@ -0,0 +1,43 @@
+package dto
+
+// StatusAudience destinatarios do status. Omitido segue a privacidade de status da conta;
+// audiencia propria ainda nao e suportada e responde 400
+type StatusAudience struct {
+	Type   string   `json:"Type" example:"contacts_except" enums:"contacts,contacts_except,only"`
+	Phones []string `json:"Phones,omitempty" example:"5511999999999"`
+}
+
+// SendTextStatusRequest request para publicar status de texto
+type SendTextStatusRequest struct {
+	Body            string          `json:"Body" example:"Hello from FioZap!"`
+	BackgroundColor string          `json:"BackgroundColor,omitempty" example:"#075E54"`
+	TextColor       string          `json:"TextColor,omitempty" example:"#FFFFFF"`
+	Font            int32           `json:"Font,omitempty" example:"0" enums:"0,1,2,6,7,8,9,10"`
+	Audience        *StatusAudience `json:"Audience,omitempty"`
+}
+
+// SendImageStatusRequest request para publicar status de imagem
+type SendImageStatusRequest struct {
+	Image    string          `json:"Image" example:"base64..."`
+	Caption  string          `json:"Caption,omitempty" example:"Status caption"`
+	MimeType string          `json:"Mimetype,omitempty" example:"image/jpeg"`
+	Audience *StatusAudience `json:"Audience,omitempty"`
+}
+
+// SendVideoStatusRequest request para publicar status de video
+type SendVideoStatusRequest struct {
+	Video    string          `json:"Video" example:"base64..."`
+	Caption  string          `json:"Caption,omitempty" example:"Status caption"`
+	MimeType string          `json:"Mimetype,omitempty" example:"video/mp4"`
+	Audience *StatusAudience `json:"Audience,omitempty"`
+}
+
+// StatusResponse status recebido de um contato
+type StatusResponse struct {
+	Id        string `json:"Id" example:"ABCD1234567890"`
+	Sender    string `json:"Sender" example:"5511999999999@s.whatsapp.net"`
+	Type      string `json:"Type" example:"image" enums:"text,image,video,audio,unknown"`
+	Body      string `json:"Body,omitempty" example:"Status caption"`
+	MimeType  string `json:"Mimetype,omitempty" example:"image/jpeg"`
+	Timestamp int64  `json:"Timestamp" example:"1704067200"`
+}
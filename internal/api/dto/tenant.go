@@ -0,0 +1,26 @@
+package dto
+
+// CreateTenantRequest criacao de tenant (cotas 0 = ilimitado)
+type CreateTenantRequest struct {
+	Name              string `json:"Name" example:"acme"`
+	MaxSessions       int    `json:"MaxSessions,omitempty" example:"10"`
+	MaxMessagesPerDay int    `json:"MaxMessagesPerDay,omitempty" example:"5000"`
+}
+
+// UpdateTenantRequest alteracao das cotas do tenant
+type UpdateTenantRequest struct {
+	MaxSessions       int `json:"MaxSessions" example:"10"`
+	MaxMessagesPerDay int `json:"MaxMessagesPerDay" example:"5000"`
+}
+
+// TenantResponse tenant com uso atual (Token so e exibido na criacao)
+type TenantResponse struct {
+	Id                string `json:"Id"`
+	Name              string `json:"Name"`
+	Token             string `json:"Token,omitempty"`
+	MaxSessions       int    `json:"MaxSessions"`
+	MaxMessagesPerDay int    `json:"MaxMessagesPerDay"`
+	Sessions          int    `json:"Sessions"`
+	MessagesToday     int    `json:"MessagesToday"`
+	CreatedAt         int64  `json:"CreatedAt"`
+}
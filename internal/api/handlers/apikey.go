@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"fiozap/internal/api/auth"
+	"fiozap/internal/api/dto"
+	"fiozap/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type APIKeyHandler struct {
+	repo repository.APIKeyRepository
+}
+
+func NewAPIKeyHandler(repo repository.APIKeyRepository) *APIKeyHandler {
+	return &APIKeyHandler{repo: repo}
+}
+
+// Create godoc
+// @Summary      Criar API key
+// @Description  Cria API key com escopos e allowlists opcionais de sessoes e IPs. A chave so e exibida nesta resposta
+// @Tags         apikeys
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.CreateAPIKeyRequest true "Dados da API key"
+// @Success      201 {object} dto.Response{data=dto.APIKeyResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /apikeys [post]
+func (h *APIKeyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dto.Error(w, http.StatusBadRequest, "could not decode Payload")
+		return
+	}
+
+	if req.Name == "" {
+		dto.Error(w, http.StatusBadRequest, "missing Name in Payload")
+		return
+	}
+	if len(req.Scopes) == 0 {
+		dto.Error(w, http.StatusBadRequest, "missing Scopes in Payload")
+		return
+	}
+	for _, scope := range req.Scopes {
+		if !auth.ValidScope(scope) {
+			dto.Error(w, http.StatusBadRequest, "invalid scope: "+scope)
+			return
+		}
+	}
+	for _, rule := range req.AllowedIPs {
+		if !auth.ValidIPRule(rule) {
+			dto.Error(w, http.StatusBadRequest, "invalid IP or CIDR: "+rule)
+			return
+		}
+	}
+
+	model := &repository.APIKeyModel{
+		ID:         uuid.New().String(),
+		Name:       req.Name,
+		Scopes:     req.Scopes,
+		Sessions:   req.Sessions,
+		AllowedIPs: req.AllowedIPs,
+	}
+	if req.ExpiresAt > 0 {
+		expiresAt := time.Unix(req.ExpiresAt, 0)
+		if !expiresAt.After(time.Now()) {
+			dto.Error(w, http.StatusBadRequest, "invalid ExpiresAt: must be in the future")
+			return
+		}
+		model.ExpiresAt = repository.NullTime(expiresAt)
+	}
+
+	key, prefix, hash := auth.GenerateAPIKey()
+	model.Prefix = prefix
+	model.KeyHash = hash
+	model.CreatedAt = time.Now()
+
+	if err := h.repo.Create(r.Context(), model); err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to save api key")
+		return
+	}
+
+	resp := apiKeyToDTO(model)
+	resp.Key = key
+	dto.Created(w, resp)
+}
+
+// List godoc
+// @Summary      Listar API keys
+// @Description  Lista as API keys (sem a chave)
+// @Tags         apikeys
+// @Produce      json
+// @Success      200 {object} dto.Response{data=[]dto.APIKeyResponse}
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /apikeys [get]
+func (h *APIKeyHandler) List(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.repo.List(r.Context())
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to list api keys")
+		return
+	}
+
+	list := make([]dto.APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		list = append(list, apiKeyToDTO(k))
+	}
+	dto.Success(w, list)
+}
+
+// Delete godoc
+// @Summary      Revogar API key
+// @Description  Remove a API key; requisicoes com ela passam a ser recusadas
+// @Tags         apikeys
+// @Produce      json
+// @Param        id path string true "ID da API key"
+// @Success      200 {object} dto.Response{data=dto.ActionResponse}
+// @Failure      404 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /apikeys/{id} [delete]
+func (h *APIKeyHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	deleted, err := h.repo.Delete(r.Context(), id)
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to delete api key")
+		return
+	}
+	if !deleted {
+		dto.Error(w, http.StatusNotFound, "api key not found")
+		return
+	}
+
+	dto.Success(w, map[string]string{"Details": "API key revoked"})
+}
+
+func apiKeyToDTO(k *repository.APIKeyModel) dto.APIKeyResponse {
+	resp := dto.APIKeyResponse{
+		Id:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		Sessions:   k.Sessions,
+		AllowedIPs: k.AllowedIPs,
+		CreatedAt:  k.CreatedAt.Unix(),
+	}
+	if resp.Sessions == nil {
+		resp.Sessions = []string{}
+	}
+	if resp.AllowedIPs == nil {
+		resp.AllowedIPs = []string{}
+	}
+	if k.ExpiresAt.Valid {
+		resp.ExpiresAt = k.ExpiresAt.Time.Unix()
+	}
+	if k.LastUsedAt.Valid {
+		resp.LastUsedAt = k.LastUsedAt.Time.Unix()
+	}
+	return resp
+}
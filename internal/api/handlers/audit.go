@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"fiozap/internal/api/dto"
+	"fiozap/internal/repository"
+)
+
+type AuditHandler struct {
+	repo repository.AuditRepository
+}
+
+func NewAuditHandler(repo repository.AuditRepository) *AuditHandler {
+	return &AuditHandler{repo: repo}
+}
+
+// List godoc
+// @Summary      Consultar auditoria
+// @Description  Lista as acoes que alteraram estado (criacao e remocao de sessoes, logout, webhooks, bloqueios, grupos, envios...), mais recentes primeiro. Apenas token global
+// @Tags         audit
+// @Produce      json
+// @Param        actorType query string false "Tipo do autor" Enums(global,tenant,apikey,session,anonymous)
+// @Param        actorId query string false "Id do tenant ou da API key, nome da sessao"
+// @Param        session query string false "Sessao alvo"
+// @Param        outcome query string false "Resultado" Enums(success,failure,denied)
+// @Param        since query int false "Desde (unix)"
+// @Param        until query int false "Ate (unix, exclusivo)"
+// @Param        limit query int false "Quantidade maxima (padrao 100, maximo 1000)"
+// @Param        offset query int false "Deslocamento (padrao 0)"
+// @Success      200 {object} dto.Response{data=dto.AuditListResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /audit [get]
+func (h *AuditHandler) List(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := repository.AuditFilter{
+		ActorType: q.Get("actorType"),
+		ActorID:   q.Get("actorId"),
+		Session:   q.Get("session"),
+		Outcome:   q.Get("outcome"),
+		Limit:     100,
+	}
+
+	switch filter.ActorType {
+	case "", "global", "tenant", "apikey", "session", "anonymous":
+	default:
+		dto.Error(w, http.StatusBadRequest, "invalid actorType. Allowed: global, tenant, apikey, session, anonymous")
+		return
+	}
+	switch filter.Outcome {
+	case "", "success", "failure", "denied":
+	default:
+		dto.Error(w, http.StatusBadRequest, "invalid outcome. Allowed: success, failure, denied")
+		return
+	}
+	for param, dst := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if v := q.Get(param); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || n < 0 {
+				dto.Error(w, http.StatusBadRequest, "invalid "+param+": expected unix timestamp")
+				return
+			}
+			*dst = time.Unix(n, 0)
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 1000 {
+			dto.Error(w, http.StatusBadRequest, "invalid limit. Allowed: 1-1000")
+			return
+		}
+		filter.Limit = n
+	}
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			dto.Error(w, http.StatusBadRequest, "invalid offset")
+			return
+		}
+		filter.Offset = n
+	}
+
+	entries, total, err := h.repo.List(r.Context(), filter)
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := dto.AuditListResponse{
+		Entries: make([]dto.AuditEntryResponse, 0, len(entries)),
+		Total:   total,
+		Limit:   filter.Limit,
+		Offset:  filter.Offset,
+	}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, dto.AuditEntryResponse{
+			ID:        e.ID,
+			ActorType: e.ActorType,
+			ActorId:   e.ActorID.String,
+			IP:        e.IP.String,
+			RequestId: e.RequestID.String,
+			Method:    e.Method,
+			Route:     e.Route,
+			Path:      e.Path,
+			Session:   e.Session.String,
+			Status:    e.Status,
+			Outcome:   e.Outcome,
+			CreatedAt: e.CreatedAt.Unix(),
+		})
+	}
+	dto.Success(w, resp)
+}
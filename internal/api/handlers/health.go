@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"fiozap/internal/api/dto"
+	"fiozap/internal/core"
+)
+
+// readyTimeout prazo das verificacoes de prontidao
+const readyTimeout = 2 * time.Second
+
+// Pinger verifica a conexao com o banco
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+type HealthHandler struct {
+	db       Pinger
+	provider core.Provider
+}
+
+func NewHealthHandler(db Pinger, provider core.Provider) *HealthHandler {
+	return &HealthHandler{db: db, provider: provider}
+}
+
+// Live godoc
+// @Summary      Liveness
+// @Description  Indica que o processo esta respondendo. Nao verifica dependencias
+// @Tags         health
+// @Produce      json
+// @Success      200 {object} dto.HealthResponse
+// @Router       /health/live [get]
+func (h *HealthHandler) Live(w http.ResponseWriter, r *http.Request) {
+	dto.JSON(w, http.StatusOK, dto.HealthResponse{Status: "ok"})
+}
+
+// Ready godoc
+// @Summary      Readiness
+// @Description  Verifica o banco (ping) e se as sessoes foram carregadas. Retorna 503 se alguma verificacao falhar ou durante o shutdown
+// @Tags         health
+// @Produce      json
+// @Success      200 {object} dto.HealthResponse
+// @Failure      503 {object} dto.HealthResponse
+// @Router       /health/ready [get]
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+	defer cancel()
+
+	resp := dto.HealthResponse{Status: "ok", Checks: map[string]string{"database": "ok", "sessions": "ok"}}
+	if err := h.db.Ping(ctx); err != nil {
+		resp.Status = "unavailable"
+		resp.Checks["database"] = err.Error()
+	}
+	if !h.provider.Ready() {
+		resp.Status = "unavailable"
+		resp.Checks["sessions"] = "not loaded or shutting down"
+	}
+
+	status := http.StatusOK
+	if resp.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+	dto.JSON(w, status, resp)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
 
 	"fiozap/internal/api/dto"
 	"fiozap/internal/api/utils"
@@ -65,6 +66,7 @@ func (h *MessageHandler) SendText(w http.ResponseWriter, r *http.Request) {
 // @Param        request body dto.SendImageRequest true "Dados da imagem (JSON)"
 // @Param        Phone formData string false "Numero do destinatario (form-data)"
 // @Param        Caption formData string false "Legenda da imagem (form-data)"
+// @Param        ViewOnce formData bool false "Visualizacao unica (form-data)"
 // @Param        file formData file false "Arquivo de imagem (form-data)"
 // @Success      200 {object} dto.Response{data=dto.MessageResponse}
 // @Failure      400 {object} dto.Response
@@ -75,6 +77,7 @@ func (h *MessageHandler) SendImage(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 
 	var phone, caption, mimeType string
+	var viewOnce bool
 	var mediaData []byte
 
 	contentType := r.Header.Get("Content-Type")
@@ -88,6 +91,7 @@ func (h *MessageHandler) SendImage(w http.ResponseWriter, r *http.Request) {
 
 		phone = r.FormValue("Phone")
 		caption = r.FormValue("Caption")
+		viewOnce = r.FormValue("ViewOnce") == "true"
 
 		media, err := utils.ProcessFormFile(r, "file")
 		if err != nil {
@@ -106,9 +110,10 @@ func (h *MessageHandler) SendImage(w http.ResponseWriter, r *http.Request) {
 
 		phone = req.Phone
 		caption = req.Caption
+		viewOnce = req.ViewOnce
 		mimeType = req.MimeType
 
-		media, err := utils.ProcessMedia(req.Image, req.MimeType)
+		media, err := utils.ProcessMedia(r.Context(), req.Image, req.MimeType)
 		if err != nil {
 			dto.Error(w, http.StatusBadRequest, err.Error())
 			return
@@ -128,7 +133,7 @@ func (h *MessageHandler) SendImage(w http.ResponseWriter, r *http.Request) {
 		mimeType = "image/jpeg"
 	}
 
-	msgId, err := h.provider.SendImage(r.Context(), name, phone, mediaData, caption, mimeType)
+	msgId, err := h.provider.SendImage(r.Context(), name, phone, mediaData, caption, mimeType, viewOnce)
 	if err != nil {
 		dto.Error(w, http.StatusInternalServerError, err.Error())
 		return
@@ -147,6 +152,7 @@ func (h *MessageHandler) SendImage(w http.ResponseWriter, r *http.Request) {
 // @Param        request body dto.SendVideoRequest true "Dados do video (JSON)"
 // @Param        Phone formData string false "Numero do destinatario (form-data)"
 // @Param        Caption formData string false "Legenda do video (form-data)"
+// @Param        ViewOnce formData bool false "Visualizacao unica (form-data)"
 // @Param        file formData file false "Arquivo de video (form-data)"
 // @Success      200 {object} dto.Response{data=dto.MessageResponse}
 // @Failure      400 {object} dto.Response
@@ -157,6 +163,7 @@ func (h *MessageHandler) SendVideo(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 
 	var phone, caption, mimeType string
+	var viewOnce bool
 	var mediaData []byte
 
 	contentType := r.Header.Get("Content-Type")
@@ -169,6 +176,7 @@ func (h *MessageHandler) SendVideo(w http.ResponseWriter, r *http.Request) {
 
 		phone = r.FormValue("Phone")
 		caption = r.FormValue("Caption")
+		viewOnce = r.FormValue("ViewOnce") == "true"
 
 		media, err := utils.ProcessFormFile(r, "file")
 		if err != nil {
@@ -186,9 +194,10 @@ func (h *MessageHandler) SendVideo(w http.ResponseWriter, r *http.Request) {
 
 		phone = req.Phone
 		caption = req.Caption
+		viewOnce = req.ViewOnce
 		mimeType = req.MimeType
 
-		media, err := utils.ProcessMedia(req.Video, req.MimeType)
+		media, err := utils.ProcessMedia(r.Context(), req.Video, req.MimeType)
 		if err != nil {
 			dto.Error(w, http.StatusBadRequest, err.Error())
 			return
@@ -208,7 +217,7 @@ func (h *MessageHandler) SendVideo(w http.ResponseWriter, r *http.Request) {
 		mimeType = "video/mp4"
 	}
 
-	msgId, err := h.provider.SendVideo(r.Context(), name, phone, mediaData, caption, mimeType)
+	msgId, err := h.provider.SendVideo(r.Context(), name, phone, mediaData, caption, mimeType, viewOnce)
 	if err != nil {
 		dto.Error(w, http.StatusInternalServerError, err.Error())
 		return
@@ -271,7 +280,7 @@ func (h *MessageHandler) SendDocument(w http.ResponseWriter, r *http.Request) {
 		fileName = req.FileName
 		mimeType = req.MimeType
 
-		media, err := utils.ProcessMedia(req.Document, req.MimeType)
+		media, err := utils.ProcessMedia(r.Context(), req.Document, req.MimeType)
 		if err != nil {
 			dto.Error(w, http.StatusBadRequest, err.Error())
 			return
@@ -317,6 +326,7 @@ func (h *MessageHandler) SendDocument(w http.ResponseWriter, r *http.Request) {
 // @Param        name path string true "Nome da sessao"
 // @Param        request body dto.SendAudioRequest true "Dados do audio (JSON)"
 // @Param        Phone formData string false "Numero do destinatario (form-data)"
+// @Param        ViewOnce formData bool false "Visualizacao unica (form-data)"
 // @Param        file formData file false "Arquivo de audio (form-data)"
 // @Success      200 {object} dto.Response{data=dto.MessageResponse}
 // @Failure      400 {object} dto.Response
@@ -327,6 +337,7 @@ func (h *MessageHandler) SendAudio(w http.ResponseWriter, r *http.Request) {
 	name := chi.URLParam(r, "name")
 
 	var phone, mimeType string
+	var viewOnce bool
 	var mediaData []byte
 
 	contentType := r.Header.Get("Content-Type")
@@ -338,6 +349,7 @@ func (h *MessageHandler) SendAudio(w http.ResponseWriter, r *http.Request) {
 		}
 
 		phone = r.FormValue("Phone")
+		viewOnce = r.FormValue("ViewOnce") == "true"
 
 		media, err := utils.ProcessFormFile(r, "file")
 		if err != nil {
@@ -354,9 +366,10 @@ func (h *MessageHandler) SendAudio(w http.ResponseWriter, r *http.Request) {
 		}
 
 		phone = req.Phone
+		viewOnce = req.ViewOnce
 		mimeType = req.MimeType
 
-		media, err := utils.ProcessMedia(req.Audio, req.MimeType)
+		media, err := utils.ProcessMedia(r.Context(), req.Audio, req.MimeType)
 		if err != nil {
 			dto.Error(w, http.StatusBadRequest, err.Error())
 			return
@@ -376,7 +389,7 @@ func (h *MessageHandler) SendAudio(w http.ResponseWriter, r *http.Request) {
 		mimeType = "audio/ogg; codecs=opus"
 	}
 
-	msgId, err := h.provider.SendAudio(r.Context(), name, phone, mediaData, mimeType)
+	msgId, err := h.provider.SendAudio(r.Context(), name, phone, mediaData, mimeType, viewOnce)
 	if err != nil {
 		dto.Error(w, http.StatusInternalServerError, err.Error())
 		return
@@ -433,7 +446,7 @@ func (h *MessageHandler) SendSticker(w http.ResponseWriter, r *http.Request) {
 		phone = req.Phone
 		mimeType = req.MimeType
 
-		media, err := utils.ProcessMedia(req.Sticker, req.MimeType)
+		media, err := utils.ProcessMedia(r.Context(), req.Sticker, req.MimeType)
 		if err != nil {
 			dto.Error(w, http.StatusBadRequest, err.Error())
 			return
@@ -614,7 +627,7 @@ func (h *MessageHandler) React(w http.ResponseWriter, r *http.Request) {
 
 // Edit godoc
 // @Summary      Editar mensagem
-// @Description  Edita uma mensagem enviada
+// @Description  Edita o texto de uma mensagem enviada ou a legenda de imagem, video ou documento (Type)
 // @Tags         messages
 // @Accept       json
 // @Produce      json
@@ -641,7 +654,14 @@ func (h *MessageHandler) Edit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	msgId, err := h.provider.EditMessage(r.Context(), name, req.Phone, messageId, req.Body)
+	switch req.Type {
+	case "", "text", "image", "video", "document":
+	default:
+		dto.Error(w, http.StatusBadRequest, "invalid Type. Allowed: text, image, video, document")
+		return
+	}
+
+	msgId, err := h.provider.EditMessage(r.Context(), name, req.Phone, messageId, req.Body, req.Type)
 	if err != nil {
 		dto.Error(w, http.StatusInternalServerError, err.Error())
 		return
@@ -652,7 +672,7 @@ func (h *MessageHandler) Edit(w http.ResponseWriter, r *http.Request) {
 
 // Revoke godoc
 // @Summary      Revogar mensagem
-// @Description  Revoga/deleta uma mensagem enviada
+// @Description  Revoga/deleta uma mensagem. Informe Sender para apagar mensagem de outro participante como admin do grupo
 // @Tags         messages
 // @Accept       json
 // @Produce      json
@@ -679,7 +699,90 @@ func (h *MessageHandler) Revoke(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	msgId, err := h.provider.RevokeMessage(r.Context(), name, req.Phone, messageId)
+	msgId, err := h.provider.RevokeMessage(r.Context(), name, req.Phone, req.Sender, messageId)
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	dto.Success(w, dto.MessageResponse{MessageId: msgId.ID})
+}
+
+// Pin godoc
+// @Summary      Fixar mensagem
+// @Description  Fixa uma mensagem no chat por 24h, 7d ou 30d (padrao 7d)
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        name path string true "Nome da sessao"
+// @Param        messageId path string true "ID da mensagem"
+// @Param        request body dto.PinMessageRequest true "Dados da mensagem"
+// @Success      200 {object} dto.Response{data=dto.MessageResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /sessions/{name}/messages/{messageId}/pin [post]
+func (h *MessageHandler) Pin(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	messageId := chi.URLParam(r, "messageId")
+
+	var req dto.PinMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dto.Error(w, http.StatusBadRequest, "could not decode Payload")
+		return
+	}
+
+	if req.Phone == "" {
+		dto.Error(w, http.StatusBadRequest, "missing Phone in Payload")
+		return
+	}
+
+	var duration time.Duration
+	switch req.Duration {
+	case "24h":
+		duration = 24 * time.Hour
+	case "7d", "":
+		duration = 7 * 24 * time.Hour
+	case "30d":
+		duration = 30 * 24 * time.Hour
+	default:
+		dto.Error(w, http.StatusBadRequest, "invalid Duration. Allowed: 24h, 7d, 30d")
+		return
+	}
+
+	msgId, err := h.provider.PinMessage(r.Context(), name, req.Phone, req.Sender, messageId, true, duration)
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	dto.Success(w, dto.MessageResponse{MessageId: msgId.ID})
+}
+
+// Unpin godoc
+// @Summary      Desafixar mensagem
+// @Description  Remove a fixacao de uma mensagem no chat
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        name path string true "Nome da sessao"
+// @Param        messageId path string true "ID da mensagem"
+// @Param        request body dto.MessageActionRequest true "Dados da mensagem"
+// @Success      200 {object} dto.Response{data=dto.MessageResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /sessions/{name}/messages/{messageId}/unpin [post]
+func (h *MessageHandler) Unpin(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	messageId := chi.URLParam(r, "messageId")
+
+	req, ok := decodeMessageAction(w, r)
+	if !ok {
+		return
+	}
+
+	msgId, err := h.provider.PinMessage(r.Context(), name, req.Phone, req.Sender, messageId, false, 0)
 	if err != nil {
 		dto.Error(w, http.StatusInternalServerError, err.Error())
 		return
@@ -687,3 +790,145 @@ func (h *MessageHandler) Revoke(w http.ResponseWriter, r *http.Request) {
 
 	dto.Success(w, dto.MessageResponse{MessageId: msgId.ID})
 }
+
+// Star godoc
+// @Summary      Favoritar mensagem
+// @Description  Marca uma mensagem com estrela
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        name path string true "Nome da sessao"
+// @Param        messageId path string true "ID da mensagem"
+// @Param        request body dto.MessageActionRequest true "Dados da mensagem"
+// @Success      200 {object} dto.Response{data=dto.MessageActionResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /sessions/{name}/messages/{messageId}/star [post]
+func (h *MessageHandler) Star(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	messageId := chi.URLParam(r, "messageId")
+
+	req, ok := decodeMessageAction(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.provider.StarMessage(r.Context(), name, req.Phone, req.Sender, messageId, true); err != nil {
+		dto.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	dto.Success(w, dto.MessageActionResponse{Details: "Message starred"})
+}
+
+// Unstar godoc
+// @Summary      Desfavoritar mensagem
+// @Description  Remove a estrela de uma mensagem
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        name path string true "Nome da sessao"
+// @Param        messageId path string true "ID da mensagem"
+// @Param        request body dto.MessageActionRequest true "Dados da mensagem"
+// @Success      200 {object} dto.Response{data=dto.MessageActionResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /sessions/{name}/messages/{messageId}/unstar [post]
+func (h *MessageHandler) Unstar(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	messageId := chi.URLParam(r, "messageId")
+
+	req, ok := decodeMessageAction(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.provider.StarMessage(r.Context(), name, req.Phone, req.Sender, messageId, false); err != nil {
+		dto.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	dto.Success(w, dto.MessageActionResponse{Details: "Message unstarred"})
+}
+
+// Keep godoc
+// @Summary      Manter mensagem
+// @Description  Mantem uma mensagem temporaria no chat
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        name path string true "Nome da sessao"
+// @Param        messageId path string true "ID da mensagem"
+// @Param        request body dto.MessageActionRequest true "Dados da mensagem"
+// @Success      200 {object} dto.Response{data=dto.MessageResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /sessions/{name}/messages/{messageId}/keep [post]
+func (h *MessageHandler) Keep(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	messageId := chi.URLParam(r, "messageId")
+
+	req, ok := decodeMessageAction(w, r)
+	if !ok {
+		return
+	}
+
+	msgId, err := h.provider.KeepMessage(r.Context(), name, req.Phone, req.Sender, messageId, true)
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	dto.Success(w, dto.MessageResponse{MessageId: msgId.ID})
+}
+
+// Unkeep godoc
+// @Summary      Liberar mensagem
+// @Description  Desfaz a manutencao de uma mensagem temporaria no chat
+// @Tags         messages
+// @Accept       json
+// @Produce      json
+// @Param        name path string true "Nome da sessao"
+// @Param        messageId path string true "ID da mensagem"
+// @Param        request body dto.MessageActionRequest true "Dados da mensagem"
+// @Success      200 {object} dto.Response{data=dto.MessageResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /sessions/{name}/messages/{messageId}/unkeep [post]
+func (h *MessageHandler) Unkeep(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	messageId := chi.URLParam(r, "messageId")
+
+	req, ok := decodeMessageAction(w, r)
+	if !ok {
+		return
+	}
+
+	msgId, err := h.provider.KeepMessage(r.Context(), name, req.Phone, req.Sender, messageId, false)
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	dto.Success(w, dto.MessageResponse{MessageId: msgId.ID})
+}
+
+// decodeMessageAction decodifica e valida o payload das acoes sobre mensagem
+func decodeMessageAction(w http.ResponseWriter, r *http.Request) (*dto.MessageActionRequest, bool) {
+	var req dto.MessageActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dto.Error(w, http.StatusBadRequest, "could not decode Payload")
+		return nil, false
+	}
+
+	if req.Phone == "" {
+		dto.Error(w, http.StatusBadRequest, "missing Phone in Payload")
+		return nil, false
+	}
+
+	return &req, true
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"fiozap/internal/api/dto"
+	"fiozap/internal/core"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type PollHandler struct {
+	provider core.Provider
+}
+
+func NewPollHandler(provider core.Provider) *PollHandler {
+	return &PollHandler{provider: provider}
+}
+
+// GetResults godoc
+// @Summary      Resultado da enquete
+// @Description  Retorna a contagem atual de votos por opcao e os votantes de uma enquete
+// @Tags         polls
+// @Produce      json
+// @Param        name path string true "Nome da sessao"
+// @Param        messageId path string true "ID da mensagem da enquete"
+// @Success      200 {object} dto.Response{data=dto.PollResultsResponse}
+// @Failure      404 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /sessions/{name}/polls/{messageId} [get]
+func (h *PollHandler) GetResults(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	messageId := chi.URLParam(r, "messageId")
+
+	results, err := h.provider.GetPollResults(r.Context(), name, messageId)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			dto.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		dto.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	options := make([]dto.PollOptionResponse, 0, len(results.Options))
+	for _, opt := range results.Options {
+		options = append(options, dto.PollOptionResponse{
+			Name:   opt.Name,
+			Count:  opt.Count,
+			Voters: opt.Voters,
+		})
+	}
+
+	dto.Success(w, dto.PollResultsResponse{
+		Id:          results.MessageID,
+		Chat:        results.ChatJID,
+		Question:    results.Question,
+		Options:     options,
+		TotalVoters: results.TotalVoters,
+	})
+}
@@ -1,17 +1,31 @@
 package handlers
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"fiozap/internal/api/auth"
 	"fiozap/internal/api/dto"
 	"fiozap/internal/core"
 
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
 	"github.com/go-chi/chi/v5"
 	"github.com/skip2/go-qrcode"
 )
 
+// streamPingInterval intervalo de keep-alive do /qr/stream
+const streamPingInterval = 15 * time.Second
+
+// loginStreamTimeout prazo total do stream de login; cobre a janela de pareamento (~160s)
+const loginStreamTimeout = 3 * time.Minute
+
 type SessionHandler struct {
 	provider core.Provider
 }
@@ -22,13 +36,14 @@ func NewSessionHandler(provider core.Provider) *SessionHandler {
 
 // Create godoc
 // @Summary      Criar sessao
-// @Description  Cria uma nova sessao WhatsApp com o nome informado
+// @Description  Cria uma nova sessao WhatsApp com o nome informado. O token so e exibido nesta resposta (o servidor guarda apenas o hash). Com token de tenant, a sessao pertence ao tenant e conta na cota. API key com allowlist de sessoes so cria sessoes da lista
 // @Tags         sessions
 // @Accept       json
 // @Produce      json
 // @Param        request body dto.CreateSessionRequest true "Nome da sessao"
 // @Success      201 {object} dto.Response{data=dto.SessionResponse}
 // @Failure      400 {object} dto.Response
+// @Failure      403 {object} dto.Response
 // @Failure      409 {object} dto.Response
 // @Security     ApiKeyAuth
 // @Router       /sessions [post]
@@ -44,36 +59,184 @@ func (h *SessionHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	session, err := h.provider.CreateSession(r.Context(), req.Name)
+	opts := core.CreateSessionOptions{
+		Proxy:          req.Proxy,
+		Metadata:       req.Metadata,
+		Tags:           req.Tags,
+		DeviceName:     req.DeviceName,
+		DevicePlatform: req.DevicePlatform,
+		IdleTimeout:    time.Duration(req.IdleTimeout) * time.Minute,
+	}
+	if req.TokenExpiresAt > 0 {
+		opts.TokenExpiresAt = time.Unix(req.TokenExpiresAt, 0)
+	}
+	if p := auth.PrincipalFromContext(r.Context()); p != nil {
+		// API key com allowlist so cria sessoes que depois conseguiria acessar
+		if !p.AllowsSession(req.Name) {
+			dto.Error(w, http.StatusForbidden, "api key not allowed for this session")
+			return
+		}
+		if p.Tenant != nil {
+			opts.TenantID = p.Tenant.ID
+			opts.MaxSessions = p.Tenant.MaxSessions
+		}
+	}
+
+	session, token, err := h.provider.CreateSession(r.Context(), req.Name, opts)
 	if err != nil {
-		dto.Error(w, http.StatusConflict, err.Error())
+		switch {
+		case strings.Contains(err.Error(), "invalid"):
+			dto.Error(w, http.StatusBadRequest, err.Error())
+		case strings.Contains(err.Error(), "quota exceeded"):
+			dto.Error(w, http.StatusForbidden, err.Error())
+		default:
+			dto.Error(w, http.StatusConflict, err.Error())
+		}
 		return
 	}
 
 	resp := sessionToDTO(session)
-	resp.Token = session.GetToken()
+	resp.Token = token.Token
+	if !token.ExpiresAt.IsZero() {
+		resp.TokenExpiresAt = token.ExpiresAt.Unix()
+	}
 	dto.Created(w, resp)
 }
 
 // List godoc
 // @Summary      Listar sessoes
-// @Description  Lista todas as sessoes WhatsApp criadas
+// @Description  Lista as sessoes WhatsApp visiveis para o token (token de tenant ve apenas as do tenant), com filtros, ordenacao e paginacao. O total sem paginacao vem no header X-Total-Count
 // @Tags         sessions
 // @Produce      json
+// @Param        status query string false "Estado da sessao" Enums(created,pairing,connecting,connected,disconnected,reconnecting,logged_out,banned,failed,idle)
+// @Param        tag query string false "Tag"
+// @Param        phone query string false "Prefixo do telefone"
+// @Param        prefix query string false "Prefixo do nome"
+// @Param        sort query string false "Ordenacao (padrao createdAt)" Enums(name,createdAt,updatedAt,status,phone)
+// @Param        order query string false "Direcao (padrao asc)" Enums(asc,desc)
+// @Param        limit query int false "Quantidade maxima (padrao 50, maximo 500)"
+// @Param        offset query int false "Deslocamento (padrao 0)"
 // @Success      200 {object} dto.Response{data=[]dto.SessionResponse}
+// @Header       200 {integer} X-Total-Count "Total de sessoes encontradas, sem paginacao"
+// @Failure      400 {object} dto.Response
+// @Failure      500 {object} dto.Response
 // @Security     ApiKeyAuth
 // @Router       /sessions [get]
 func (h *SessionHandler) List(w http.ResponseWriter, r *http.Request) {
-	sessions := h.provider.ListSessions()
+	q := r.URL.Query()
+	query := core.SessionQuery{
+		Status:     core.SessionStatus(q.Get("status")),
+		Tag:        q.Get("tag"),
+		Phone:      q.Get("phone"),
+		NamePrefix: q.Get("prefix"),
+		SortBy:     q.Get("sort"),
+		Limit:      50,
+	}
+
+	switch query.SortBy {
+	case "", "name", "createdAt", "updatedAt", "status", "phone":
+	default:
+		dto.Error(w, http.StatusBadRequest, "invalid sort. Allowed: name, createdAt, updatedAt, status, phone")
+		return
+	}
+	switch q.Get("order") {
+	case "", "asc":
+	case "desc":
+		query.Desc = true
+	default:
+		dto.Error(w, http.StatusBadRequest, "invalid order. Allowed: asc, desc")
+		return
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 500 {
+			dto.Error(w, http.StatusBadRequest, "invalid limit. Allowed: 1-500")
+			return
+		}
+		query.Limit = n
+	}
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			dto.Error(w, http.StatusBadRequest, "invalid offset")
+			return
+		}
+		query.Offset = n
+	}
+
+	// Isolamento aplicado na busca para manter o total e a paginacao corretos
+	if p := auth.PrincipalFromContext(r.Context()); p != nil {
+		if p.Tenant != nil {
+			query.TenantID = p.Tenant.ID
+		}
+		if p.Key != nil && len(p.Key.Sessions) > 0 {
+			query.Names = p.Key.Sessions
+		}
+	}
+
+	sessions, total, err := h.provider.SearchSessions(r.Context(), query)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid") {
+			dto.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		dto.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
 
 	list := make([]dto.SessionResponse, 0, len(sessions))
 	for _, s := range sessions {
 		list = append(list, sessionToDTO(s))
 	}
 
+	// data continua sendo a lista; o total sem paginacao vai no header
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
 	dto.Success(w, list)
 }
 
+// SetLabels godoc
+// @Summary      Definir metadados e tags
+// @Description  Substitui os metadados chave/valor e as tags da sessao (ate 50 de cada)
+// @Tags         sessions
+// @Accept       json
+// @Produce      json
+// @Param        name path string true "Nome da sessao"
+// @Param        request body dto.SetSessionLabelsRequest true "Metadados e tags"
+// @Success      200 {object} dto.Response{data=dto.SessionResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      404 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /sessions/{name}/labels [put]
+func (h *SessionHandler) SetLabels(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	var req dto.SetSessionLabelsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dto.Error(w, http.StatusBadRequest, "could not decode Payload")
+		return
+	}
+
+	if err := h.provider.SetSessionLabels(r.Context(), name, req.Metadata, req.Tags); err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			dto.Error(w, http.StatusNotFound, err.Error())
+		case strings.Contains(err.Error(), "invalid"):
+			dto.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			dto.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	session, err := h.provider.GetSession(name)
+	if err != nil {
+		dto.Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	dto.Success(w, sessionToDTO(session))
+}
+
 // Get godoc
 // @Summary      Obter sessao
 // @Description  Retorna informacoes de uma sessao especifica
@@ -165,6 +328,285 @@ func (h *SessionHandler) GetQR(w http.ResponseWriter, r *http.Request) {
 	dto.Success(w, dto.QRResponse{QRCode: code})
 }
 
+// StreamQR godoc
+// @Summary      Stream do login
+// @Description  Envia em tempo real cada novo QR (texto e PNG em data URL), codigo de pareamento, timeout, sucesso e erro do login. Usa Server-Sent Events; com header Upgrade: websocket usa WebSocket com as mesmas mensagens JSON. Encerra apos o evento final (connected, timeout ou error) ou, sem resultado, com um evento timeout apos 3 minutos
+// @Tags         sessions
+// @Produce      text/event-stream
+// @Param        name path string true "Nome da sessao"
+// @Success      200 {object} dto.LoginEventResponse
+// @Failure      404 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /sessions/{name}/qr/stream [get]
+func (h *SessionHandler) StreamQR(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	session, err := h.provider.GetSession(name)
+	if err != nil {
+		dto.Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	events, cancel, err := h.provider.SubscribeLogin(name)
+	if err != nil {
+		dto.Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	defer cancel()
+
+	// Sessao ja conectada: entrega apenas o evento final
+	if session.IsConnected() {
+		done := make(chan core.LoginEvent, 1)
+		done <- core.LoginEvent{Event: core.LoginEventConnected, JID: session.GetJID()}
+		events = done
+	}
+
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		streamLoginWebSocket(w, r, events)
+		return
+	}
+	streamLoginSSE(w, r, events)
+}
+
+// streamLoginSSE envia os eventos de login como Server-Sent Events
+func streamLoginSSE(w http.ResponseWriter, r *http.Request, events <-chan core.LoginEvent) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		dto.Error(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ping := time.NewTicker(streamPingInterval)
+	defer ping.Stop()
+	deadline := time.NewTimer(loginStreamTimeout)
+	defer deadline.Stop()
+
+	send := func(evt core.LoginEvent) bool {
+		data, err := json.Marshal(loginEventToDTO(evt))
+		if err != nil {
+			return false
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", evt.Event, data); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-deadline.C:
+			send(loginDeadlineEvent())
+			return
+		case <-ping.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		case evt, ok := <-events:
+			if !ok || !send(evt) || finalLoginEvent(evt) {
+				return
+			}
+		}
+	}
+}
+
+// streamLoginWebSocket envia os eventos de login como mensagens JSON via WebSocket
+func streamLoginWebSocket(w http.ResponseWriter, r *http.Request, events <-chan core.LoginEvent) {
+	// A autenticacao e por token, nao por cookie, entao a verificacao de origem nao protege nada
+	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{InsecureSkipVerify: true})
+	if err != nil {
+		return
+	}
+	defer func() { _ = conn.CloseNow() }()
+
+	// Descarta mensagens do cliente e cancela o contexto quando ele fecha
+	ctx := conn.CloseRead(r.Context())
+
+	ping := time.NewTicker(streamPingInterval)
+	defer ping.Stop()
+	deadline := time.NewTimer(loginStreamTimeout)
+	defer deadline.Stop()
+
+	send := func(evt core.LoginEvent) bool {
+		writeCtx, cancel := context.WithTimeout(ctx, streamPingInterval)
+		defer cancel()
+		return wsjson.Write(writeCtx, conn, loginEventToDTO(evt)) == nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-deadline.C:
+			evt := loginDeadlineEvent()
+			if send(evt) {
+				_ = conn.Close(websocket.StatusNormalClosure, evt.Event)
+			}
+			return
+		case <-ping.C:
+			pingCtx, cancel := context.WithTimeout(ctx, streamPingInterval)
+			err := conn.Ping(pingCtx)
+			cancel()
+			if err != nil {
+				return
+			}
+		case evt, ok := <-events:
+			if !ok {
+				_ = conn.Close(websocket.StatusGoingAway, "login stream closed")
+				return
+			}
+			if !send(evt) {
+				return
+			}
+			if finalLoginEvent(evt) {
+				_ = conn.Close(websocket.StatusNormalClosure, evt.Event)
+				return
+			}
+		}
+	}
+}
+
+// finalLoginEvent indica se o evento encerra o fluxo de login. success nao encerra: ainda vem
+// connected ou error
+func finalLoginEvent(evt core.LoginEvent) bool {
+	switch evt.Event {
+	case core.LoginEventConnected, core.LoginEventTimeout, core.LoginEventError:
+		return true
+	}
+	return false
+}
+
+// loginDeadlineEvent evento final enviado quando o stream atinge loginStreamTimeout
+func loginDeadlineEvent() core.LoginEvent {
+	return core.LoginEvent{Event: core.LoginEventTimeout, Error: "login stream deadline exceeded"}
+}
+
+// loginEventToDTO converte o evento e gera o PNG do QR em data URL
+func loginEventToDTO(evt core.LoginEvent) dto.LoginEventResponse {
+	resp := dto.LoginEventResponse{
+		Event: evt.Event,
+		Code:  evt.Code,
+		JID:   evt.JID,
+		Error: evt.Error,
+	}
+	if !evt.ExpiresAt.IsZero() {
+		resp.ExpiresAt = evt.ExpiresAt.Unix()
+	}
+	if evt.Event == core.LoginEventCode && evt.Code != "" {
+		if png, err := qrcode.Encode(evt.Code, qrcode.Medium, 256); err == nil {
+			resp.Image = "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+		}
+	}
+	return resp
+}
+
+// Pair godoc
+// @Summary      Parear por codigo
+// @Description  Gera codigo de pareamento de 8 caracteres para vincular o numero sem QR code. O codigo expira junto com a janela de login (~160s); chame novamente para gerar outro
+// @Tags         sessions
+// @Accept       json
+// @Produce      json
+// @Param        name path string true "Nome da sessao"
+// @Param        request body dto.PairPhoneRequest true "Numero de telefone com DDI"
+// @Success      200 {object} dto.Response{data=dto.PairingCodeResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      404 {object} dto.Response
+// @Failure      409 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /sessions/{name}/pair [post]
+func (h *SessionHandler) Pair(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	var req dto.PairPhoneRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dto.Error(w, http.StatusBadRequest, "could not decode Payload")
+		return
+	}
+
+	if req.Phone == "" {
+		dto.Error(w, http.StatusBadRequest, "missing Phone in Payload")
+		return
+	}
+
+	code, err := h.provider.PairPhone(r.Context(), name, req.Phone)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			dto.Error(w, http.StatusNotFound, err.Error())
+		case strings.Contains(err.Error(), "already paired"):
+			dto.Error(w, http.StatusConflict, err.Error())
+		case strings.Contains(err.Error(), "invalid phone"):
+			dto.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			dto.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	dto.Success(w, dto.PairingCodeResponse{Code: code.Code, ExpiresAt: code.ExpiresAt.Unix()})
+}
+
+// History godoc
+// @Summary      Historico de estados
+// @Description  Lista as transicoes de estado da sessao, mais recentes primeiro
+// @Tags         sessions
+// @Produce      json
+// @Param        name path string true "Nome da sessao"
+// @Param        limit query int false "Quantidade maxima (padrao 50, maximo 500)"
+// @Success      200 {object} dto.Response{data=[]dto.SessionStatusChangeResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      404 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /sessions/{name}/history [get]
+func (h *SessionHandler) History(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	limit := 50
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 500 {
+			dto.Error(w, http.StatusBadRequest, "invalid limit. Allowed: 1-500")
+			return
+		}
+		limit = n
+	}
+
+	history, err := h.provider.GetSessionHistory(r.Context(), name, limit)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			dto.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		dto.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	list := make([]dto.SessionStatusChangeResponse, 0, len(history))
+	for _, c := range history {
+		list = append(list, dto.SessionStatusChangeResponse{
+			From:      string(c.From),
+			To:        string(c.To),
+			Reason:    c.Reason,
+			Timestamp: c.At.Unix(),
+		})
+	}
+
+	dto.Success(w, list)
+}
+
 // Disconnect godoc
 // @Summary      Desconectar sessao
 // @Description  Desconecta a sessao do WhatsApp (mantem dados)
@@ -208,9 +650,285 @@ func (h *SessionHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	dto.Success(w, map[string]string{"Details": "Session deleted"})
 }
 
+// RotateToken godoc
+// @Summary      Rotacionar token
+// @Description  Gera novo token da sessao. O token anterior continua valido durante GracePeriod segundos (0 revoga na hora). O novo token so e exibido nesta resposta
+// @Tags         sessions
+// @Accept       json
+// @Produce      json
+// @Param        name path string true "Nome da sessao"
+// @Param        request body dto.RotateTokenRequest false "Carencia e expiracao"
+// @Success      200 {object} dto.Response{data=dto.TokenResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      404 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /sessions/{name}/token/rotate [post]
+func (h *SessionHandler) RotateToken(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	var req dto.RotateTokenRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			dto.Error(w, http.StatusBadRequest, "could not decode Payload")
+			return
+		}
+	}
+
+	var expiresAt time.Time
+	if req.ExpiresAt > 0 {
+		expiresAt = time.Unix(req.ExpiresAt, 0)
+	}
+
+	token, err := h.provider.RotateToken(r.Context(), name, time.Duration(req.GracePeriod)*time.Second, expiresAt)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			dto.Error(w, http.StatusNotFound, err.Error())
+		case strings.Contains(err.Error(), "invalid"):
+			dto.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			dto.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	resp := dto.TokenResponse{Token: token.Token}
+	if !token.ExpiresAt.IsZero() {
+		resp.ExpiresAt = token.ExpiresAt.Unix()
+	}
+	if !token.PreviousExpiresAt.IsZero() {
+		resp.PreviousTokenExpiresAt = token.PreviousExpiresAt.Unix()
+	}
+	dto.Success(w, resp)
+}
+
+// Health godoc
+// @Summary      Saude da sessao
+// @Description  Diagnostico de conectividade: websocket, login, ultimo evento, ultimo timeout de keepalive, prekeys disponiveis no servidor e ultimo erro de envio. Retorna 503 se a sessao nao estiver saudavel
+// @Tags         sessions
+// @Produce      json
+// @Param        name path string true "Nome da sessao"
+// @Success      200 {object} dto.Response{data=dto.SessionHealthResponse}
+// @Failure      404 {object} dto.Response
+// @Failure      503 {object} dto.Response{data=dto.SessionHealthResponse}
+// @Security     ApiKeyAuth
+// @Router       /sessions/{name}/health [get]
+func (h *SessionHandler) Health(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	health, err := h.provider.GetSessionHealth(r.Context(), name)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			dto.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		dto.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := dto.SessionHealthResponse{
+		Name:                 health.Name,
+		Status:               string(health.Status),
+		Healthy:              health.Healthy,
+		Owned:                health.Owned,
+		WebsocketConnected:   health.WebsocketConnected,
+		LoggedIn:             health.LoggedIn,
+		LastConnectedAt:      unixOrZero(health.LastConnectedAt),
+		LastEventAt:          unixOrZero(health.LastEventAt),
+		LastKeepAliveTimeout: unixOrZero(health.LastKeepAliveTimeout),
+		UploadedPreKeys:      health.UploadedPreKeys,
+		LastSendError:        health.LastSendError,
+		LastSendErrorAt:      unixOrZero(health.LastSendErrorAt),
+	}
+	if !health.Healthy {
+		dto.JSON(w, http.StatusServiceUnavailable, dto.Response{
+			Code:    http.StatusServiceUnavailable,
+			Success: false,
+			Data:    resp,
+			Error:   "session unhealthy",
+		})
+		return
+	}
+	dto.Success(w, resp)
+}
+
+// SetIdleTimeout godoc
+// @Summary      Configurar inatividade
+// @Description  Desconecta a sessao apos N minutos sem uso da API, mantendo o pareamento (estado idle). A proxima chamada que precisar da conexao reconecta e aguarda o WhatsApp antes de prosseguir. 0 desativa
+// @Tags         sessions
+// @Accept       json
+// @Produce      json
+// @Param        name path string true "Nome da sessao"
+// @Param        request body dto.SetIdleTimeoutRequest true "Minutos sem uso"
+// @Success      200 {object} dto.Response{data=dto.SessionResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      404 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /sessions/{name}/idle [put]
+func (h *SessionHandler) SetIdleTimeout(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	var req dto.SetIdleTimeoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dto.Error(w, http.StatusBadRequest, "could not decode Payload")
+		return
+	}
+
+	if err := h.provider.SetIdleTimeout(r.Context(), name, time.Duration(req.IdleTimeout)*time.Minute); err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			dto.Error(w, http.StatusNotFound, err.Error())
+		case strings.Contains(err.Error(), "invalid"):
+			dto.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			dto.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	session, err := h.provider.GetSession(name)
+	if err != nil {
+		dto.Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	dto.Success(w, sessionToDTO(session))
+}
+
+// SetProxy godoc
+// @Summary      Configurar proxy
+// @Description  Define o proxy HTTP/SOCKS5 da sessao, usado no websocket e em upload/download de midia. Sessao conectada reconecta pelo novo proxy. Proxy vazio remove
+// @Tags         sessions
+// @Accept       json
+// @Produce      json
+// @Param        name path string true "Nome da sessao"
+// @Param        request body dto.SetProxyRequest true "Proxy"
+// @Success      200 {object} dto.Response{data=dto.SessionResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      404 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /sessions/{name}/proxy [put]
+func (h *SessionHandler) SetProxy(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	var req dto.SetProxyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dto.Error(w, http.StatusBadRequest, "could not decode Payload")
+		return
+	}
+
+	if err := h.provider.SetProxy(r.Context(), name, req.Proxy); err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			dto.Error(w, http.StatusNotFound, err.Error())
+		case strings.Contains(err.Error(), "invalid"):
+			dto.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			dto.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	session, err := h.provider.GetSession(name)
+	if err != nil {
+		dto.Error(w, http.StatusNotFound, err.Error())
+		return
+	}
+	dto.Success(w, sessionToDTO(session))
+}
+
+// Export godoc
+// @Summary      Exportar sessao
+// @Description  Exporta a sessao pareada (dados, webhook e chaves do WhatsApp) num arquivo cifrado com a senha informada, para importar em outra instalacao sem novo QR. Com Detach a sessao e desconectada aqui. Apenas token global
+// @Tags         sessions
+// @Accept       json
+// @Produce      json
+// @Param        name path string true "Nome da sessao"
+// @Param        request body dto.ExportSessionRequest true "Senha e desligamento"
+// @Success      200 {object} dto.Response{data=dto.ExportSessionResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      403 {object} dto.Response
+// @Failure      404 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /sessions/{name}/export [post]
+func (h *SessionHandler) Export(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	var req dto.ExportSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dto.Error(w, http.StatusBadRequest, "could not decode Payload")
+		return
+	}
+
+	archive, err := h.provider.ExportSession(r.Context(), name, req.Passphrase, req.Detach)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			dto.Error(w, http.StatusNotFound, err.Error())
+		case strings.Contains(err.Error(), "invalid"), strings.Contains(err.Error(), "not paired"):
+			dto.Error(w, http.StatusBadRequest, err.Error())
+		default:
+			dto.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	dto.Success(w, dto.ExportSessionResponse{
+		Name:       name,
+		Archive:    base64.StdEncoding.EncodeToString(archive.Data),
+		Detached:   archive.Detached,
+		ExportedAt: archive.ExportedAt.Unix(),
+	})
+}
+
+// Import godoc
+// @Summary      Importar sessao
+// @Description  Cria a sessao a partir de um arquivo exportado, ja pareada e com o mesmo token. Recusa se o numero ja existe aqui ou se o arquivo foi exportado sem Detach (use Force se a origem ja estiver desligada). Conecte depois com /connect. Apenas token global
+// @Tags         sessions
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.ImportSessionRequest true "Arquivo e senha"
+// @Success      201 {object} dto.Response{data=dto.SessionResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      409 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /sessions/import [post]
+func (h *SessionHandler) Import(w http.ResponseWriter, r *http.Request) {
+	var req dto.ImportSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dto.Error(w, http.StatusBadRequest, "could not decode Payload")
+		return
+	}
+
+	data, err := base64.StdEncoding.DecodeString(req.Archive)
+	if err != nil || len(data) == 0 {
+		dto.Error(w, http.StatusBadRequest, "invalid Archive: expected base64")
+		return
+	}
+
+	session, err := h.provider.ImportSession(r.Context(), data, req.Passphrase, req.Force)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "invalid"):
+			dto.Error(w, http.StatusBadRequest, err.Error())
+		case strings.Contains(err.Error(), "already"), strings.Contains(err.Error(), "live elsewhere"), strings.Contains(err.Error(), "duplicate key"):
+			dto.Error(w, http.StatusConflict, err.Error())
+		default:
+			dto.Error(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	dto.Created(w, sessionToDTO(session))
+}
+
 // Logout godoc
 // @Summary      Logout da sessao
-// @Description  Faz logout da sessao (requer novo QR code para reconectar)
+// @Description  Faz logout e remove o device da sessao. Nome e token sao mantidos; conecte de novo para parear com novo QR ou codigo
 // @Tags         sessions
 // @Produce      json
 // @Param        name path string true "Nome da sessao"
@@ -231,10 +949,26 @@ func (h *SessionHandler) Logout(w http.ResponseWriter, r *http.Request) {
 
 func sessionToDTO(s core.Session) dto.SessionResponse {
 	return dto.SessionResponse{
-		Name:      s.GetName(),
-		JID:       s.GetJID(),
-		Phone:     s.GetPhone(),
-		PushName:  s.GetPushName(),
-		Connected: s.IsConnected(),
+		Name:           s.GetName(),
+		TenantId:       s.GetTenantID(),
+		Proxy:          s.GetProxy(),
+		Metadata:       s.GetMetadata(),
+		Tags:           s.GetTags(),
+		DeviceName:     s.GetDeviceName(),
+		DevicePlatform: s.GetDevicePlatform(),
+		IdleTimeout:    int(s.GetIdleTimeout() / time.Minute),
+		JID:            s.GetJID(),
+		Phone:          s.GetPhone(),
+		PushName:       s.GetPushName(),
+		Connected:      s.IsConnected(),
+		Status:         string(s.GetStatus()),
+	}
+}
+
+// unixOrZero converte para unix; data zero vira 0
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
 	}
+	return t.Unix()
 }
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"fiozap/internal/api/auth"
+	"fiozap/internal/core"
+	"fiozap/internal/repository"
+)
+
+func TestStreamLoginSSEEndsOnFinalEvent(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []core.LoginEvent
+		want   string
+	}{
+		{"connected", []core.LoginEvent{{Event: core.LoginEventCode, Code: "qr"}, {Event: core.LoginEventConnected}}, "event: connected"},
+		{"timeout", []core.LoginEvent{{Event: core.LoginEventTimeout}}, "event: timeout"},
+		{"error", []core.LoginEvent{{Event: core.LoginEventError, Error: "boom"}}, "event: error"},
+		{"success then error", []core.LoginEvent{{Event: core.LoginEventSuccess}, {Event: core.LoginEventError, Error: "connect failure"}}, "event: error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := make(chan core.LoginEvent, len(tt.events))
+			for _, evt := range tt.events {
+				events <- evt
+			}
+
+			w := httptest.NewRecorder()
+			done := make(chan struct{})
+			go func() {
+				streamLoginSSE(w, httptest.NewRequest("GET", "/qr/stream", nil), events)
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(2 * time.Second):
+				t.Fatal("stream did not end after final event")
+			}
+			body := w.Body.String()
+			if !strings.HasSuffix(strings.TrimSpace(body[:strings.LastIndex(body, "data:")]), tt.want) {
+				t.Errorf("last event = %q, want %q", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamLoginSSEKeepsOpenAfterSuccess(t *testing.T) {
+	events := make(chan core.LoginEvent, 1)
+	events <- core.LoginEvent{Event: core.LoginEventSuccess}
+
+	done := make(chan struct{})
+	go func() {
+		streamLoginSSE(httptest.NewRecorder(), httptest.NewRequest("GET", "/qr/stream", nil), events)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("stream ended on success; connected or error must follow")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(events)
+	<-done
+}
+
+// searchProvider guarda a consulta recebida por SearchSessions
+type searchProvider struct {
+	core.Provider
+	query core.SessionQuery
+}
+
+func (p *searchProvider) SearchSessions(_ context.Context, query core.SessionQuery) ([]core.Session, int, error) {
+	p.query = query
+	return nil, 7, nil
+}
+
+func TestListScopesQueryToPrincipal(t *testing.T) {
+	tests := []struct {
+		name       string
+		principal  *auth.Principal
+		wantTenant string
+		wantNames  []string
+	}{
+		{name: "global", principal: &auth.Principal{Global: true}},
+		{name: "tenant", principal: &auth.Principal{Tenant: &repository.TenantModel{ID: "tenant-a"}}, wantTenant: "tenant-a"},
+		{name: "api key with allowlist", principal: &auth.Principal{Key: &repository.APIKeyModel{Sessions: []string{"vendas", "suporte"}}}, wantNames: []string{"vendas", "suporte"}},
+		{name: "api key without allowlist", principal: &auth.Principal{Key: &repository.APIKeyModel{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &searchProvider{}
+			req := httptest.NewRequest(http.MethodGet, "/sessions", nil)
+			req = req.WithContext(context.WithValue(req.Context(), auth.CtxKeyPrincipal, tt.principal))
+			w := httptest.NewRecorder()
+			NewSessionHandler(provider).List(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200 (%s)", w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), `"data":[]`) || w.Header().Get("X-Total-Count") != "7" {
+				t.Fatalf("body = %s, X-Total-Count = %q; want data array and total 7", w.Body.String(), w.Header().Get("X-Total-Count"))
+			}
+			if provider.query.TenantID != tt.wantTenant || !reflect.DeepEqual(provider.query.Names, tt.wantNames) {
+				t.Fatalf("query TenantID = %q Names = %v, want %q %v", provider.query.TenantID, provider.query.Names, tt.wantTenant, tt.wantNames)
+			}
+		})
+	}
+}
+
+// createProvider registra os nomes recebidos por CreateSession
+type createProvider struct {
+	core.Provider
+	created []string
+}
+
+func (p *createProvider) CreateSession(_ context.Context, name string, _ core.CreateSessionOptions) (core.Session, *core.SessionToken, error) {
+	p.created = append(p.created, name)
+	return nil, nil, errors.New("session " + name + " already exists")
+}
+
+func TestCreateRespectsAPIKeyAllowlist(t *testing.T) {
+	tests := []struct {
+		name        string
+		principal   *auth.Principal
+		session     string
+		wantCreated bool
+	}{
+		{name: "key allowed", principal: &auth.Principal{Key: &repository.APIKeyModel{Sessions: []string{"vendas"}}}, session: "vendas", wantCreated: true},
+		{name: "key not allowed", principal: &auth.Principal{Key: &repository.APIKeyModel{Sessions: []string{"vendas"}}}, session: "outra", wantCreated: false},
+		{name: "key without allowlist", principal: &auth.Principal{Key: &repository.APIKeyModel{}}, session: "outra", wantCreated: true},
+		{name: "tenant", principal: &auth.Principal{Tenant: &repository.TenantModel{ID: "t1"}}, session: "outra", wantCreated: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &createProvider{}
+			req := httptest.NewRequest(http.MethodPost, "/sessions", strings.NewReader(`{"Name":"`+tt.session+`"}`))
+			req = req.WithContext(context.WithValue(req.Context(), auth.CtxKeyPrincipal, tt.principal))
+			w := httptest.NewRecorder()
+			NewSessionHandler(provider).Create(w, req)
+
+			if created := len(provider.created) > 0; created != tt.wantCreated {
+				t.Fatalf("created = %v, want %v (status %d)", created, tt.wantCreated, w.Code)
+			}
+			if !tt.wantCreated && w.Code != http.StatusForbidden {
+				t.Fatalf("status = %d, want 403", w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"fiozap/internal/api/dto"
+	"fiozap/internal/api/utils"
+	"fiozap/internal/core"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type StatusHandler struct {
+	provider core.Provider
+}
+
+func NewStatusHandler(provider core.Provider) *StatusHandler {
+	return &StatusHandler{provider: provider}
+}
+
+// SendText godoc
+// @Summary      Publicar status de texto
+// @Description  Publica status de texto com cor de fundo e fonte. Segue a privacidade de status da conta; Audience ainda nao e suportado e responde 400
+// @Tags         status
+// @Accept       json
+// @Produce      json
+// @Param        name path string true "Nome da sessao"
+// @Param        request body dto.SendTextStatusRequest true "Dados do status"
+// @Success      200 {object} dto.Response{data=dto.MessageResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /sessions/{name}/status/text [post]
+func (h *StatusHandler) SendText(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	var req dto.SendTextStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dto.Error(w, http.StatusBadRequest, "could not decode Payload")
+		return
+	}
+
+	if req.Body == "" {
+		dto.Error(w, http.StatusBadRequest, "missing Body in Payload")
+		return
+	}
+
+	background, err := parseColor(req.BackgroundColor, 0xFF075E54)
+	if err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid BackgroundColor: "+err.Error())
+		return
+	}
+
+	textColor, err := parseColor(req.TextColor, 0xFFFFFFFF)
+	if err != nil {
+		dto.Error(w, http.StatusBadRequest, "invalid TextColor: "+err.Error())
+		return
+	}
+
+	switch req.Font {
+	case 0, 1, 2, 6, 7, 8, 9, 10:
+	default:
+		dto.Error(w, http.StatusBadRequest, "invalid Font. Allowed: 0, 1, 2, 6, 7, 8, 9, 10")
+		return
+	}
+
+	msgId, err := h.provider.SendTextStatus(r.Context(), name, req.Body, background, textColor, req.Font, toStatusAudience(req.Audience))
+	if err != nil {
+		statusSendError(w, err)
+		return
+	}
+
+	dto.Success(w, dto.MessageResponse{MessageId: msgId.ID})
+}
+
+// SendImage godoc
+// @Summary      Publicar status de imagem
+// @Description  Publica imagem como status. Aceita base64, data URL, URL publica ou form-data. Segue a privacidade de status da conta; Audience ainda nao e suportado e responde 400
+// @Tags         status
+// @Accept       json,multipart/form-data
+// @Produce      json
+// @Param        name path string true "Nome da sessao"
+// @Param        request body dto.SendImageStatusRequest true "Dados da imagem (JSON)"
+// @Param        Caption formData string false "Legenda (form-data)"
+// @Param        AudienceType formData string false "Audiencia (ainda nao suportada, responde 400) (form-data)"
+// @Param        AudiencePhones formData string false "Telefones da audiencia separados por virgula (form-data)"
+// @Param        file formData file false "Arquivo de imagem (form-data)"
+// @Success      200 {object} dto.Response{data=dto.MessageResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /sessions/{name}/status/image [post]
+func (h *StatusHandler) SendImage(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	var caption, mimeType string
+	var mediaData []byte
+	var audience *dto.StatusAudience
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(50 << 20); err != nil { // 50MB max
+			dto.Error(w, http.StatusBadRequest, "failed to parse multipart form")
+			return
+		}
+
+		caption = r.FormValue("Caption")
+		audience = formStatusAudience(r)
+
+		media, err := utils.ProcessFormFile(r, "file")
+		if err != nil {
+			dto.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		mediaData = media.Data
+		mimeType = media.MimeType
+	} else {
+		var req dto.SendImageStatusRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			dto.Error(w, http.StatusBadRequest, "could not decode Payload")
+			return
+		}
+
+		caption = req.Caption
+		mimeType = req.MimeType
+		audience = req.Audience
+
+		media, err := utils.ProcessMedia(r.Context(), req.Image, req.MimeType)
+		if err != nil {
+			dto.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		mediaData = media.Data
+		if mimeType == "" {
+			mimeType = media.MimeType
+		}
+	}
+
+	if mimeType == "" {
+		mimeType = "image/jpeg"
+	}
+
+	msgId, err := h.provider.SendImageStatus(r.Context(), name, mediaData, caption, mimeType, toStatusAudience(audience))
+	if err != nil {
+		statusSendError(w, err)
+		return
+	}
+
+	dto.Success(w, dto.MessageResponse{MessageId: msgId.ID})
+}
+
+// SendVideo godoc
+// @Summary      Publicar status de video
+// @Description  Publica video como status. Aceita base64, data URL, URL publica ou form-data. Segue a privacidade de status da conta; Audience ainda nao e suportado e responde 400
+// @Tags         status
+// @Accept       json,multipart/form-data
+// @Produce      json
+// @Param        name path string true "Nome da sessao"
+// @Param        request body dto.SendVideoStatusRequest true "Dados do video (JSON)"
+// @Param        Caption formData string false "Legenda (form-data)"
+// @Param        AudienceType formData string false "Audiencia (ainda nao suportada, responde 400) (form-data)"
+// @Param        AudiencePhones formData string false "Telefones da audiencia separados por virgula (form-data)"
+// @Param        file formData file false "Arquivo de video (form-data)"
+// @Success      200 {object} dto.Response{data=dto.MessageResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /sessions/{name}/status/video [post]
+func (h *StatusHandler) SendVideo(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	var caption, mimeType string
+	var mediaData []byte
+	var audience *dto.StatusAudience
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(100 << 20); err != nil { // 100MB max
+			dto.Error(w, http.StatusBadRequest, "failed to parse multipart form")
+			return
+		}
+
+		caption = r.FormValue("Caption")
+		audience = formStatusAudience(r)
+
+		media, err := utils.ProcessFormFile(r, "file")
+		if err != nil {
+			dto.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		mediaData = media.Data
+		mimeType = media.MimeType
+	} else {
+		var req dto.SendVideoStatusRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			dto.Error(w, http.StatusBadRequest, "could not decode Payload")
+			return
+		}
+
+		caption = req.Caption
+		mimeType = req.MimeType
+		audience = req.Audience
+
+		media, err := utils.ProcessMedia(r.Context(), req.Video, req.MimeType)
+		if err != nil {
+			dto.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		mediaData = media.Data
+		if mimeType == "" {
+			mimeType = media.MimeType
+		}
+	}
+
+	if mimeType == "" {
+		mimeType = "video/mp4"
+	}
+
+	msgId, err := h.provider.SendVideoStatus(r.Context(), name, mediaData, caption, mimeType, toStatusAudience(audience))
+	if err != nil {
+		statusSendError(w, err)
+		return
+	}
+
+	dto.Success(w, dto.MessageResponse{MessageId: msgId.ID})
+}
+
+// List godoc
+// @Summary      Listar status recebidos
+// @Description  Lista status (stories) recebidos dos contatos, mais recentes primeiro
+// @Tags         status
+// @Produce      json
+// @Param        name path string true "Nome da sessao"
+// @Param        phone query string false "Filtra pelo contato que publicou"
+// @Param        limit query int false "Quantidade maxima (padrao 50, maximo 500)"
+// @Success      200 {object} dto.Response{data=[]dto.StatusResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /sessions/{name}/status [get]
+func (h *StatusHandler) List(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	limit := 50
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 500 {
+			dto.Error(w, http.StatusBadRequest, "invalid limit. Allowed: 1-500")
+			return
+		}
+		limit = n
+	}
+
+	statuses, err := h.provider.ListStatuses(r.Context(), name, r.URL.Query().Get("phone"), limit)
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	list := make([]dto.StatusResponse, 0, len(statuses))
+	for _, s := range statuses {
+		list = append(list, dto.StatusResponse{
+			Id:        s.ID,
+			Sender:    s.SenderJID,
+			Type:      s.Type,
+			Body:      s.Body,
+			MimeType:  s.MimeType,
+			Timestamp: s.Timestamp.Unix(),
+		})
+	}
+
+	dto.Success(w, list)
+}
+
+// parseColor converte "#RRGGBB" ou "#AARRGGBB" para ARGB
+func parseColor(color string, defaultValue uint32) (uint32, error) {
+	if color == "" {
+		return defaultValue, nil
+	}
+
+	hex := strings.TrimPrefix(color, "#")
+	if len(hex) != 6 && len(hex) != 8 {
+		return 0, fmt.Errorf("expected #RRGGBB or #AARRGGBB")
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("expected #RRGGBB or #AARRGGBB")
+	}
+	if len(hex) == 6 {
+		v |= 0xFF000000
+	}
+	return uint32(v), nil
+}
+
+func toStatusAudience(a *dto.StatusAudience) *core.StatusAudience {
+	if a == nil {
+		return nil
+	}
+	return &core.StatusAudience{Type: a.Type, Phones: a.Phones}
+}
+
+// formStatusAudience le a audiencia dos campos AudienceType e AudiencePhones (form-data)
+func formStatusAudience(r *http.Request) *dto.StatusAudience {
+	audienceType := r.FormValue("AudienceType")
+	if audienceType == "" {
+		return nil
+	}
+	audience := &dto.StatusAudience{Type: audienceType}
+	for _, phone := range strings.Split(r.FormValue("AudiencePhones"), ",") {
+		if phone = strings.TrimSpace(phone); phone != "" {
+			audience.Phones = append(audience.Phones, phone)
+		}
+	}
+	return audience
+}
+
+func statusSendError(w http.ResponseWriter, err error) {
+	if strings.Contains(err.Error(), "invalid audience") {
+		dto.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	dto.Error(w, http.StatusInternalServerError, err.Error())
+}
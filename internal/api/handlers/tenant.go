@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"fiozap/internal/api/auth"
+	"fiozap/internal/api/dto"
+	"fiozap/internal/core"
+	"fiozap/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type TenantHandler struct {
+	repo     repository.TenantRepository
+	provider core.Provider
+}
+
+func NewTenantHandler(repo repository.TenantRepository, provider core.Provider) *TenantHandler {
+	return &TenantHandler{repo: repo, provider: provider}
+}
+
+// Create godoc
+// @Summary      Criar tenant
+// @Description  Cria tenant com token de admin proprio e cotas. O token so e exibido nesta resposta
+// @Tags         tenants
+// @Accept       json
+// @Produce      json
+// @Param        request body dto.CreateTenantRequest true "Dados do tenant"
+// @Success      201 {object} dto.Response{data=dto.TenantResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      409 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /tenants [post]
+func (h *TenantHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateTenantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dto.Error(w, http.StatusBadRequest, "could not decode Payload")
+		return
+	}
+
+	if req.Name == "" {
+		dto.Error(w, http.StatusBadRequest, "missing Name in Payload")
+		return
+	}
+	if req.MaxSessions < 0 || req.MaxMessagesPerDay < 0 {
+		dto.Error(w, http.StatusBadRequest, "quotas must not be negative")
+		return
+	}
+
+	token, hash := auth.GenerateTenantToken()
+	tenant := &repository.TenantModel{
+		ID:                uuid.New().String(),
+		Name:              req.Name,
+		TokenHash:         hash,
+		MaxSessions:       req.MaxSessions,
+		MaxMessagesPerDay: req.MaxMessagesPerDay,
+		CreatedAt:         time.Now(),
+	}
+	if err := h.repo.Create(r.Context(), tenant); err != nil {
+		dto.Error(w, http.StatusConflict, "tenant already exists")
+		return
+	}
+
+	resp := h.tenantToDTO(r, tenant)
+	resp.Token = token
+	dto.Created(w, resp)
+}
+
+// List godoc
+// @Summary      Listar tenants
+// @Description  Lista tenants com cotas e uso atual
+// @Tags         tenants
+// @Produce      json
+// @Success      200 {object} dto.Response{data=[]dto.TenantResponse}
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /tenants [get]
+func (h *TenantHandler) List(w http.ResponseWriter, r *http.Request) {
+	tenants, err := h.repo.List(r.Context())
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to list tenants")
+		return
+	}
+
+	list := make([]dto.TenantResponse, 0, len(tenants))
+	for _, t := range tenants {
+		list = append(list, h.tenantToDTO(r, t))
+	}
+	dto.Success(w, list)
+}
+
+// Update godoc
+// @Summary      Alterar cotas do tenant
+// @Description  Altera as cotas de sessoes e mensagens por dia (0 = ilimitado). Sessoes existentes acima da cota sao mantidas
+// @Tags         tenants
+// @Accept       json
+// @Produce      json
+// @Param        id path string true "ID do tenant"
+// @Param        request body dto.UpdateTenantRequest true "Cotas"
+// @Success      200 {object} dto.Response{data=dto.TenantResponse}
+// @Failure      400 {object} dto.Response
+// @Failure      404 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /tenants/{id} [put]
+func (h *TenantHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var req dto.UpdateTenantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dto.Error(w, http.StatusBadRequest, "could not decode Payload")
+		return
+	}
+	if req.MaxSessions < 0 || req.MaxMessagesPerDay < 0 {
+		dto.Error(w, http.StatusBadRequest, "quotas must not be negative")
+		return
+	}
+
+	tenant, err := h.repo.Get(r.Context(), id)
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to load tenant")
+		return
+	}
+	if tenant == nil {
+		dto.Error(w, http.StatusNotFound, "tenant not found")
+		return
+	}
+
+	tenant.MaxSessions = req.MaxSessions
+	tenant.MaxMessagesPerDay = req.MaxMessagesPerDay
+	if err := h.repo.UpdateQuotas(r.Context(), tenant); err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to update tenant")
+		return
+	}
+
+	dto.Success(w, h.tenantToDTO(r, tenant))
+}
+
+// Delete godoc
+// @Summary      Remover tenant
+// @Description  Remove o tenant. Falha enquanto ele tiver sessoes
+// @Tags         tenants
+// @Produce      json
+// @Param        id path string true "ID do tenant"
+// @Success      200 {object} dto.Response{data=dto.ActionResponse}
+// @Failure      404 {object} dto.Response
+// @Failure      409 {object} dto.Response
+// @Failure      500 {object} dto.Response
+// @Security     ApiKeyAuth
+// @Router       /tenants/{id} [delete]
+func (h *TenantHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if h.countSessions(id) > 0 {
+		dto.Error(w, http.StatusConflict, "tenant still has sessions")
+		return
+	}
+
+	deleted, err := h.repo.Delete(r.Context(), id)
+	if err != nil {
+		dto.Error(w, http.StatusInternalServerError, "failed to delete tenant")
+		return
+	}
+	if !deleted {
+		dto.Error(w, http.StatusNotFound, "tenant not found")
+		return
+	}
+
+	dto.Success(w, map[string]string{"Details": "Tenant deleted"})
+}
+
+func (h *TenantHandler) countSessions(tenantID string) int {
+	n := 0
+	for _, s := range h.provider.ListSessions() {
+		if s.GetTenantID() == tenantID {
+			n++
+		}
+	}
+	return n
+}
+
+func (h *TenantHandler) tenantToDTO(r *http.Request, t *repository.TenantModel) dto.TenantResponse {
+	messages, _ := h.repo.MessagesOn(r.Context(), t.ID, time.Now().UTC())
+	return dto.TenantResponse{
+		Id:                t.ID,
+		Name:              t.Name,
+		MaxSessions:       t.MaxSessions,
+		MaxMessagesPerDay: t.MaxMessagesPerDay,
+		Sessions:          h.countSessions(t.ID),
+		MessagesToday:     messages,
+		CreatedAt:         t.CreatedAt.Unix(),
+	}
+}
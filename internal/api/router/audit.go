@@ -0,0 +1,95 @@
+package router
+
+import (
+	"context"
+	"net/http"
+
+	"fiozap/internal/api/auth"
+	"fiozap/internal/audit"
+	"fiozap/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+type auditCtxKey struct{}
+
+// auditState estado compartilhado entre o middleware de auditoria e os seguintes
+type auditState struct {
+	forwarded bool // encaminhada para a instancia dona, que registra a acao
+}
+
+// markForwarded evita registro duplicado: quem audita e a instancia que atende a requisicao
+func markForwarded(ctx context.Context) {
+	if state, ok := ctx.Value(auditCtxKey{}).(*auditState); ok {
+		state.forwarded = true
+	}
+}
+
+// audited indica se o metodo altera estado
+func audited(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// auditLog registra na trilha de auditoria toda requisicao que altera estado: autor (tipo e id do
+// token), IP, request id, rota, sessao alvo e resultado. Negadas pela autenticacao tambem ficam.
+// A gravacao e assincrona (audit.Writer) para nao atrasar a resposta
+func auditLog(writer *audit.Writer) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !audited(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			state := &auditState{}
+			ctx, principal := auth.TrackPrincipal(context.WithValue(r.Context(), auditCtxKey{}, state))
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+			if state.forwarded {
+				return
+			}
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			outcome := "success"
+			switch {
+			case status == http.StatusUnauthorized || status == http.StatusForbidden:
+				outcome = "denied"
+			case status >= 400:
+				outcome = "failure"
+			}
+
+			route := r.URL.Path
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			ip := ""
+			if clientIP := auth.ClientIP(r); clientIP != nil {
+				ip = clientIP.String()
+			}
+			kind, id := principal().Actor()
+
+			entry := &repository.AuditModel{
+				ActorType: kind,
+				ActorID:   repository.NullString(id),
+				IP:        repository.NullString(ip),
+				RequestID: repository.NullString(middleware.GetReqID(r.Context())),
+				Method:    r.Method,
+				Route:     route,
+				Path:      r.URL.Path,
+				Session:   repository.NullString(chi.URLParam(r, "name")),
+				Status:    status,
+				Outcome:   outcome,
+			}
+
+			writer.Record(entry)
+		})
+	}
+}
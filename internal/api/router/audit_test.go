@@ -0,0 +1,102 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"fiozap/internal/api/auth"
+	"fiozap/internal/audit"
+	"fiozap/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog"
+)
+
+// fakeAuditRepo guarda os registros gravados pelo audit.Writer
+type fakeAuditRepo struct {
+	mu      sync.Mutex
+	entries []*repository.AuditModel
+}
+
+func (f *fakeAuditRepo) Create(_ context.Context, entry *repository.AuditModel) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+func (f *fakeAuditRepo) List(context.Context, repository.AuditFilter) ([]*repository.AuditModel, int, error) {
+	return nil, 0, nil
+}
+
+func TestAuditLog(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		token       string
+		status      int
+		forwarded   bool
+		wantEntry   bool
+		wantOutcome string
+		wantActor   string
+	}{
+		{name: "success", method: http.MethodPost, token: "global", status: http.StatusOK, wantEntry: true, wantOutcome: "success", wantActor: "global"},
+		{name: "implicit 200", method: http.MethodDelete, token: "global", wantEntry: true, wantOutcome: "success", wantActor: "global"},
+		{name: "unauthorized", method: http.MethodPost, token: "wrong", wantEntry: true, wantOutcome: "denied", wantActor: "anonymous"},
+		{name: "forbidden", method: http.MethodPut, token: "global", status: http.StatusForbidden, wantEntry: true, wantOutcome: "denied", wantActor: "global"},
+		{name: "client error", method: http.MethodPost, token: "global", status: http.StatusBadRequest, wantEntry: true, wantOutcome: "failure", wantActor: "global"},
+		{name: "server error", method: http.MethodPatch, token: "global", status: http.StatusInternalServerError, wantEntry: true, wantOutcome: "failure", wantActor: "global"},
+		{name: "read only", method: http.MethodGet, token: "global", status: http.StatusOK},
+		{name: "forwarded", method: http.MethodPost, token: "global", status: http.StatusOK, forwarded: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeAuditRepo{}
+			writer := audit.NewWriter(repo, zerolog.Nop())
+			authMiddleware := auth.NewAuth("global", nil, &repository.Repositories{}, zerolog.Nop())
+
+			r := chi.NewRouter()
+			r.Use(middleware.RequestID)
+			r.Use(auditLog(writer))
+			r.With(authMiddleware.Global).MethodFunc(tt.method, "/sessions/{name}/logout", func(w http.ResponseWriter, r *http.Request) {
+				if tt.forwarded {
+					markForwarded(r.Context())
+				}
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+			})
+
+			req := httptest.NewRequest(tt.method, "/sessions/vendas/logout", nil)
+			req.Header.Set("Authorization", tt.token)
+			r.ServeHTTP(httptest.NewRecorder(), req)
+			if err := writer.Shutdown(context.Background()); err != nil {
+				t.Fatalf("Shutdown: %v", err)
+			}
+
+			if !tt.wantEntry {
+				if len(repo.entries) != 0 {
+					t.Fatalf("entries = %d, want none", len(repo.entries))
+				}
+				return
+			}
+			if len(repo.entries) != 1 {
+				t.Fatalf("entries = %d, want 1", len(repo.entries))
+			}
+			e := repo.entries[0]
+			if e.Outcome != tt.wantOutcome || e.ActorType != tt.wantActor {
+				t.Errorf("outcome = %q actor = %q, want %q %q", e.Outcome, e.ActorType, tt.wantOutcome, tt.wantActor)
+			}
+			if e.Route != "/sessions/{name}/logout" || e.Path != "/sessions/vendas/logout" || e.Session.String != "vendas" {
+				t.Errorf("route = %q path = %q session = %q", e.Route, e.Path, e.Session.String)
+			}
+			if e.Method != tt.method || !e.RequestID.Valid {
+				t.Errorf("method = %q request id = %q", e.Method, e.RequestID.String)
+			}
+		})
+	}
+}
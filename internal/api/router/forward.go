@@ -0,0 +1,70 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+
+	"fiozap/internal/api/dto"
+	"fiozap/internal/core"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// forwardedHeader marca requisicoes ja encaminhadas entre instancias (evita loop)
+const forwardedHeader = "X-Fiozap-Forwarded-By"
+
+// forwardToOwner encaminha rotas de sessao para a instancia dona do lease.
+// Usar antes da autenticacao: quem valida o token e a instancia dona
+func forwardToOwner(provider core.Provider, logger zerolog.Logger) func(next http.Handler) http.Handler {
+	log := logger.With().Str("component", "forward").Logger()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			owner, err := provider.SessionOwner(r.Context(), chi.URLParam(r, "name"))
+			if err != nil || owner.Local {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.Header.Get(forwardedHeader) != "" {
+				dto.Error(w, http.StatusServiceUnavailable, "session owner unavailable, retry shortly")
+				return
+			}
+			if owner.Address == "" {
+				dto.Error(w, http.StatusConflict, "session is served by instance "+owner.InstanceID)
+				return
+			}
+			target, err := url.Parse(owner.Address)
+			if err != nil {
+				dto.Error(w, http.StatusBadGateway, "invalid address for instance "+owner.InstanceID)
+				return
+			}
+
+			proxy := &httputil.ReverseProxy{
+				Rewrite: func(pr *httputil.ProxyRequest) {
+					pr.SetURL(target)
+					pr.SetXForwarded()
+					pr.Out.Host = pr.In.Host
+					pr.Out.Header.Set(forwardedHeader, owner.InstanceID)
+					// Mesmo request id nos logs e na auditoria da instancia dona
+					if reqID := middleware.GetReqID(pr.In.Context()); reqID != "" {
+						pr.Out.Header.Set(middleware.RequestIDHeader, reqID)
+					}
+				},
+				// Propaga o trace context para a instancia dona
+				Transport: otelhttp.NewTransport(http.DefaultTransport),
+				// Sem buffer para SSE do QR
+				FlushInterval: -1,
+				ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+					log.Warn().Err(err).Str("instance", owner.InstanceID).Str("path", r.URL.Path).Msg("Failed to forward request to session owner")
+					dto.Error(w, http.StatusBadGateway, "failed to reach session owner")
+				},
+			}
+			markForwarded(r.Context())
+			proxy.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+
+	"fiozap/internal/api/auth"
+	"fiozap/internal/api/dto"
+	"fiozap/internal/metrics"
+	"fiozap/internal/ratelimit"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+)
+
+// rateLimiter middlewares de rate limiting. Com limiter nil nao limita nada
+type rateLimiter struct {
+	limiter *ratelimit.Limiter
+	log     zerolog.Logger
+}
+
+func newRateLimiter(limiter *ratelimit.Limiter, logger zerolog.Logger) *rateLimiter {
+	return &rateLimiter{limiter: limiter, log: logger.With().Str("component", "ratelimit").Logger()}
+}
+
+// Token limita por credencial autenticada (token global, de tenant, de sessao ou API key); usar
+// depois da autenticacao, para que tokens invalidos nao criem buckets. Sem principal usa o IP
+func (rl *rateLimiter) Token(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rl.serve(w, r, next, ratelimit.ScopeToken, tokenKey(r))
+	})
+}
+
+// tokenKey chave do bucket por credencial: tipo e id do principal ou, sem ele, o IP do cliente
+func tokenKey(r *http.Request) string {
+	p := auth.PrincipalFromContext(r.Context())
+	if p == nil {
+		return "ip:" + auth.ClientIP(r).String()
+	}
+	kind, id := p.Actor()
+	return kind + ":" + id
+}
+
+// Session limita por sessao, somando todos os tokens que a acessam. scope e ScopeSession ou
+// uma classe de rota (ClassSend, ClassContactCheck, ClassGroupWrite)
+func (rl *rateLimiter) Session(scope string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rl.serve(w, r, next, scope, chi.URLParam(r, "name"))
+		})
+	}
+}
+
+func (rl *rateLimiter) serve(w http.ResponseWriter, r *http.Request, next http.Handler, scope, key string) {
+	decision, err := rl.limiter.Allow(r.Context(), scope, key)
+	if err != nil {
+		// Falha do armazenamento (ex.: Redis fora) nao derruba a API
+		rl.log.Warn().Err(err).Str("scope", scope).Msg("Rate limit check failed, allowing request")
+		next.ServeHTTP(w, r)
+		return
+	}
+	if !decision.Allowed {
+		metrics.RateLimited.WithLabelValues(scope).Inc()
+		w.Header().Set("Retry-After", strconv.Itoa(decision.RetryAfterSeconds()))
+		dto.Error(w, http.StatusTooManyRequests, "rate limit exceeded ("+scope+")")
+		return
+	}
+	next.ServeHTTP(w, r)
+}
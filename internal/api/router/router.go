@@ -1,33 +1,52 @@
 package router
 
 import (
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"fiozap/docs"
 	"fiozap/internal/api/auth"
 	"fiozap/internal/api/handlers"
+	"fiozap/internal/audit"
 	"fiozap/internal/core"
 	"fiozap/internal/integrations/webhook"
+	"fiozap/internal/metrics"
+	"fiozap/internal/ratelimit"
+	"fiozap/internal/repository"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/rs/zerolog"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func New(provider core.Provider, logger zerolog.Logger, globalToken string, webhookDispatcher *webhook.Dispatcher) http.Handler {
+func New(provider core.Provider, repos *repository.Repositories, db handlers.Pinger, logger zerolog.Logger, globalToken, metricsToken string, webhookDispatcher *webhook.Dispatcher, limiter *ratelimit.Limiter, trustedProxies []*net.IPNet, auditWriter *audit.Writer) http.Handler {
 	r := chi.NewRouter()
 
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
+	r.Use(auth.RealIP(trustedProxies))
 	r.Use(requestLogger(logger))
-	r.Use(middleware.Timeout(60 * time.Second))
+	r.Use(otelhttp.NewMiddleware("fiozap", otelhttp.WithFilter(traced)))
+	r.Use(instrument)
+	r.Use(auditLog(auditWriter))
+	r.Use(timeoutExceptStreams(60 * time.Second))
 
-	authMiddleware := auth.NewAuth(globalToken, provider)
+	authMiddleware := auth.NewAuth(globalToken, provider, repos, logger)
+	scope := authMiddleware.Require
+	limit := newRateLimiter(limiter, logger)
+	sendLimit := limit.Session(ratelimit.ClassSend)
+	groupWriteLimit := limit.Session(ratelimit.ClassGroupWrite)
 	sessionHandler := handlers.NewSessionHandler(provider)
 	messageHandler := handlers.NewMessageHandler(provider)
+	pollHandler := handlers.NewPollHandler(provider)
+	statusHandler := handlers.NewStatusHandler(provider)
 	contactHandler := handlers.NewContactHandler(provider)
 	groupHandler := handlers.NewGroupHandler(provider)
 	chatHandler := handlers.NewChatHandler(provider)
@@ -37,11 +56,15 @@ func New(provider core.Provider, logger zerolog.Logger, globalToken string, webh
 	privacyHandler := handlers.NewPrivacyHandler(provider)
 	profileHandler := handlers.NewProfileHandler(provider)
 	webhookHandler := handlers.NewWebhookHandler(webhookDispatcher)
+	apiKeyHandler := handlers.NewAPIKeyHandler(repos.APIKey)
+	tenantHandler := handlers.NewTenantHandler(repos.Tenant, provider)
+	healthHandler := handlers.NewHealthHandler(db, provider)
+	auditHandler := handlers.NewAuditHandler(repos.Audit)
 
-	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		_, _ = w.Write([]byte(`{"status":"ok"}`))
-	})
+	r.Get("/health", healthHandler.Live)
+	r.Get("/health/live", healthHandler.Live)
+	r.Get("/health/ready", healthHandler.Ready)
+	r.Handle("/metrics", metrics.Handler(metricsToken))
 
 	// Swagger with dynamic host
 	r.Get("/swagger/*", httpSwagger.Handler(
@@ -65,134 +88,188 @@ func New(provider core.Provider, logger zerolog.Logger, globalToken string, webh
 	})
 
 	r.Route("/sessions", func(r chi.Router) {
-		r.With(authMiddleware.Global).Post("/", sessionHandler.Create)
-		r.With(authMiddleware.Global).Get("/", sessionHandler.List)
+		r.Group(func(r chi.Router) {
+			r.With(authMiddleware.Admin, limit.Token, scope(auth.ScopeSessionsAdmin)).Post("/", sessionHandler.Create)
+			r.With(authMiddleware.Admin, limit.Token, scope(auth.ScopeSessionsAdmin)).Get("/", sessionHandler.List)
+			r.With(authMiddleware.Global, limit.Token).Post("/import", sessionHandler.Import)
+		})
 
 		r.Route("/{name}", func(r chi.Router) {
+			r.Use(forwardToOwner(provider, logger))
+			// Limites aplicados so na instancia dona, apos o encaminhamento e a autenticacao
 			r.Use(authMiddleware.Session)
+			r.Use(limit.Token)
+			r.Use(limit.Session(ratelimit.ScopeSession))
 
 			// Session
-			r.Get("/", sessionHandler.Get)
-			r.Post("/connect", sessionHandler.Connect)
-			r.Get("/qr", sessionHandler.GetQR)
-			r.Post("/disconnect", sessionHandler.Disconnect)
-			r.Post("/logout", sessionHandler.Logout)
-			r.Delete("/", sessionHandler.Delete)
+			r.With(scope(auth.ScopeSessionsRead)).Get("/", sessionHandler.Get)
+			r.With(scope(auth.ScopeSessionsRead)).Get("/history", sessionHandler.History)
+			r.With(scope(auth.ScopeSessionsRead)).Get("/health", sessionHandler.Health)
+			r.With(scope(auth.ScopeSessionsAdmin)).Post("/token/rotate", sessionHandler.RotateToken)
+			r.With(scope(auth.ScopeSessionsAdmin)).Put("/proxy", sessionHandler.SetProxy)
+			r.With(scope(auth.ScopeSessionsAdmin)).Put("/idle", sessionHandler.SetIdleTimeout)
+			r.With(scope(auth.ScopeSessionsWrite)).Put("/labels", sessionHandler.SetLabels)
+			r.With(authMiddleware.GlobalOnly).Post("/export", sessionHandler.Export)
+			r.With(scope(auth.ScopeSessionsWrite)).Post("/connect", sessionHandler.Connect)
+			r.With(scope(auth.ScopeSessionsRead)).Get("/qr", sessionHandler.GetQR)
+			r.With(scope(auth.ScopeSessionsRead)).Get("/qr/stream", sessionHandler.StreamQR)
+			r.With(scope(auth.ScopeSessionsWrite)).Post("/pair", sessionHandler.Pair)
+			r.With(scope(auth.ScopeSessionsWrite)).Post("/disconnect", sessionHandler.Disconnect)
+			r.With(scope(auth.ScopeSessionsWrite)).Post("/logout", sessionHandler.Logout)
+			r.With(scope(auth.ScopeSessionsAdmin)).Delete("/", sessionHandler.Delete)
 
 			// Messages
 			r.Route("/messages", func(r chi.Router) {
-				r.Post("/text", messageHandler.SendText)
-				r.Post("/image", messageHandler.SendImage)
-				r.Post("/video", messageHandler.SendVideo)
-				r.Post("/audio", messageHandler.SendAudio)
-				r.Post("/document", messageHandler.SendDocument)
-				r.Post("/sticker", messageHandler.SendSticker)
-				r.Post("/location", messageHandler.SendLocation)
-				r.Post("/contact", messageHandler.SendContact)
-				r.Post("/poll", messageHandler.SendPoll)
-				r.Post("/reaction", messageHandler.React)
-				r.Put("/{messageId}", messageHandler.Edit)
-				r.Delete("/{messageId}", messageHandler.Revoke)
+				r.Use(scope(auth.ScopeMessagesSend))
+				r.Group(func(r chi.Router) {
+					r.Use(sendLimit)
+					r.Use(authMiddleware.MessageQuota)
+					r.Post("/text", messageHandler.SendText)
+					r.Post("/image", messageHandler.SendImage)
+					r.Post("/video", messageHandler.SendVideo)
+					r.Post("/audio", messageHandler.SendAudio)
+					r.Post("/document", messageHandler.SendDocument)
+					r.Post("/sticker", messageHandler.SendSticker)
+					r.Post("/location", messageHandler.SendLocation)
+					r.Post("/contact", messageHandler.SendContact)
+					r.Post("/poll", messageHandler.SendPoll)
+					r.Post("/reaction", messageHandler.React)
+					r.Put("/{messageId}", messageHandler.Edit)
+					r.Delete("/{messageId}", messageHandler.Revoke)
+					r.Post("/{messageId}/pin", messageHandler.Pin)
+					r.Post("/{messageId}/unpin", messageHandler.Unpin)
+					r.Post("/{messageId}/keep", messageHandler.Keep)
+					r.Post("/{messageId}/unkeep", messageHandler.Unkeep)
+				})
+				// Favoritar so altera o app state: nao envia mensagem nem consome cota
+				r.Post("/{messageId}/star", messageHandler.Star)
+				r.Post("/{messageId}/unstar", messageHandler.Unstar)
+			})
+
+			// Polls
+			r.With(scope(auth.ScopeMessagesRead)).Get("/polls/{messageId}", pollHandler.GetResults)
+
+			// Status (stories)
+			r.Route("/status", func(r chi.Router) {
+				r.With(scope(auth.ScopeMessagesRead)).Get("/", statusHandler.List)
+				r.Group(func(r chi.Router) {
+					r.Use(scope(auth.ScopeMessagesSend))
+					r.Use(sendLimit)
+					r.Use(authMiddleware.MessageQuota)
+					r.Post("/text", statusHandler.SendText)
+					r.Post("/image", statusHandler.SendImage)
+					r.Post("/video", statusHandler.SendVideo)
+				})
 			})
 
 			// Contacts
-			r.Post("/contacts/check", contactHandler.CheckPhone)
-			r.Get("/contacts/{phone}", contactHandler.GetInfo)
-			r.Get("/contacts/{phone}/avatar", contactHandler.GetAvatar)
-			r.Get("/contacts/{phone}/business", contactHandler.GetBusinessProfile)
+			r.Group(func(r chi.Router) {
+				r.Use(scope(auth.ScopeContactsRead))
+				r.With(limit.Session(ratelimit.ClassContactCheck)).Post("/contacts/check", contactHandler.CheckPhone)
+				r.Get("/contacts/{phone}", contactHandler.GetInfo)
+				r.Get("/contacts/{phone}/avatar", contactHandler.GetAvatar)
+				r.Get("/contacts/{phone}/business", contactHandler.GetBusinessProfile)
+			})
 
 			// Groups
 			r.Route("/groups", func(r chi.Router) {
-				r.Post("/", groupHandler.Create)
-				r.Get("/", groupHandler.List)
-				r.Post("/join", groupHandler.Join)
-				r.Get("/invite/{code}", groupHandler.GetInviteInfo)
+				r.With(scope(auth.ScopeGroupsWrite), groupWriteLimit).Post("/", groupHandler.Create)
+				r.With(scope(auth.ScopeGroupsRead)).Get("/", groupHandler.List)
+				r.With(scope(auth.ScopeGroupsWrite), groupWriteLimit).Post("/join", groupHandler.Join)
+				r.With(scope(auth.ScopeGroupsRead)).Get("/invite/{code}", groupHandler.GetInviteInfo)
 
 				r.Route("/{groupJid}", func(r chi.Router) {
-					r.Get("/", groupHandler.Get)
-					r.Put("/name", groupHandler.SetName)
-					r.Put("/topic", groupHandler.SetTopic)
-					r.Put("/photo", groupHandler.SetPhoto)
-					r.Post("/leave", groupHandler.Leave)
-					r.Get("/invite", groupHandler.GetInviteLink)
-					r.Post("/invite/revoke", groupHandler.RevokeInviteLink)
-
-					// Participants
-					r.Route("/participants", func(r chi.Router) {
-						r.Post("/", groupHandler.AddParticipants)
-						r.Delete("/", groupHandler.RemoveParticipants)
-						r.Post("/promote", groupHandler.PromoteParticipants)
-						r.Post("/demote", groupHandler.DemoteParticipants)
-					})
+					r.With(scope(auth.ScopeGroupsRead)).Get("/", groupHandler.Get)
+					r.With(scope(auth.ScopeGroupsRead)).Get("/invite", groupHandler.GetInviteLink)
+					r.With(scope(auth.ScopeGroupsRead)).Get("/requests", groupHandler.GetRequestParticipants)
+
+					r.Group(func(r chi.Router) {
+						r.Use(scope(auth.ScopeGroupsWrite))
+						r.Use(groupWriteLimit)
+						r.Put("/name", groupHandler.SetName)
+						r.Put("/topic", groupHandler.SetTopic)
+						r.Put("/photo", groupHandler.SetPhoto)
+						r.Post("/leave", groupHandler.Leave)
+						r.Post("/invite/revoke", groupHandler.RevokeInviteLink)
+
+						// Participants
+						r.Route("/participants", func(r chi.Router) {
+							r.Post("/", groupHandler.AddParticipants)
+							r.Delete("/", groupHandler.RemoveParticipants)
+							r.Post("/promote", groupHandler.PromoteParticipants)
+							r.Post("/demote", groupHandler.DemoteParticipants)
+						})
 
-					// Settings
-					r.Put("/settings/announce", groupHandler.SetAnnounce)
-					r.Put("/settings/locked", groupHandler.SetLocked)
-					r.Put("/settings/approval", groupHandler.SetApproval)
+						// Settings
+						r.Put("/settings/announce", groupHandler.SetAnnounce)
+						r.Put("/settings/locked", groupHandler.SetLocked)
+						r.Put("/settings/approval", groupHandler.SetApproval)
+						r.Put("/settings/memberadd", groupHandler.SetMemberAddMode)
+
+						// Group Request Participants
+						r.Post("/requests/approve", groupHandler.ApproveRequestParticipants)
+						r.Post("/requests/reject", groupHandler.RejectRequestParticipants)
+					})
 				})
 			})
 
 			// Chat
-			r.Post("/chat/markread", chatHandler.MarkRead)
-			r.Post("/chat/presence", chatHandler.Presence)
-			r.Route("/chat/{chatJid}", func(r chi.Router) {
-				r.Put("/disappearing", chatHandler.SetDisappearing)
-			})
+			r.Group(func(r chi.Router) {
+				r.Use(scope(auth.ScopeChatsWrite))
+				r.Post("/chat/markread", chatHandler.MarkRead)
+				r.Post("/chat/presence", chatHandler.Presence)
+				r.Put("/chat/{chatJid}/disappearing", chatHandler.SetDisappearing)
 
-			// Presence (global)
-			r.Post("/presence", chatHandler.SendPresence)
-			r.Post("/presence/subscribe", chatHandler.SubscribePresence)
+				// Presence (global)
+				r.Post("/presence", chatHandler.SendPresence)
+				r.Post("/presence/subscribe", chatHandler.SubscribePresence)
+			})
 
 			// Blocklist
-			r.Get("/blocklist", blocklistHandler.GetBlocklist)
-			r.Post("/blocklist/block", blocklistHandler.Block)
-			r.Post("/blocklist/unblock", blocklistHandler.Unblock)
+			r.With(scope(auth.ScopeContactsRead)).Get("/blocklist", blocklistHandler.GetBlocklist)
+			r.With(scope(auth.ScopeContactsWrite)).Post("/blocklist/block", blocklistHandler.Block)
+			r.With(scope(auth.ScopeContactsWrite)).Post("/blocklist/unblock", blocklistHandler.Unblock)
 
 			// Newsletter (Channels)
 			r.Route("/newsletters", func(r chi.Router) {
-				r.Post("/", newsletterHandler.Create)
-				r.Get("/", newsletterHandler.List)
+				r.With(scope(auth.ScopeNewslettersWrite)).Post("/", newsletterHandler.Create)
+				r.With(scope(auth.ScopeNewslettersRead)).Get("/", newsletterHandler.List)
 				r.Route("/{newsletterJid}", func(r chi.Router) {
-					r.Get("/", newsletterHandler.Get)
-					r.Post("/follow", newsletterHandler.Follow)
-					r.Post("/unfollow", newsletterHandler.Unfollow)
-					r.Put("/mute", newsletterHandler.ToggleMute)
-					r.Post("/reaction", newsletterHandler.SendReaction)
+					r.With(scope(auth.ScopeNewslettersRead)).Get("/", newsletterHandler.Get)
+					r.With(scope(auth.ScopeNewslettersWrite)).Post("/follow", newsletterHandler.Follow)
+					r.With(scope(auth.ScopeNewslettersWrite)).Post("/unfollow", newsletterHandler.Unfollow)
+					r.With(scope(auth.ScopeNewslettersWrite)).Put("/mute", newsletterHandler.ToggleMute)
+					r.With(scope(auth.ScopeNewslettersWrite)).Post("/reaction", newsletterHandler.SendReaction)
 				})
 			})
 
 			// Privacy
 			r.Route("/privacy", func(r chi.Router) {
-				r.Get("/", privacyHandler.GetSettings)
-				r.Put("/", privacyHandler.SetSetting)
-				r.Get("/status", privacyHandler.GetStatusPrivacy)
+				r.With(scope(auth.ScopePrivacyRead)).Get("/", privacyHandler.GetSettings)
+				r.With(scope(auth.ScopePrivacyWrite)).Put("/", privacyHandler.SetSetting)
+				r.With(scope(auth.ScopePrivacyRead)).Get("/status", privacyHandler.GetStatusPrivacy)
 			})
 
 			// Profile
 			r.Route("/profile", func(r chi.Router) {
-				r.Get("/qrlink", profileHandler.GetContactQRLink)
-				r.Post("/qrlink/resolve", profileHandler.ResolveContactQRLink)
-				r.Put("/status", profileHandler.SetStatusMessage)
-				r.Post("/business/resolve", profileHandler.ResolveBusinessMessageLink)
+				r.With(scope(auth.ScopeProfileRead)).Get("/qrlink", profileHandler.GetContactQRLink)
+				r.With(scope(auth.ScopeProfileRead)).Post("/qrlink/resolve", profileHandler.ResolveContactQRLink)
+				r.With(scope(auth.ScopeProfileWrite)).Put("/status", profileHandler.SetStatusMessage)
+				r.With(scope(auth.ScopeProfileRead)).Post("/business/resolve", profileHandler.ResolveBusinessMessageLink)
 			})
 
-			// Group Request Participants
-			r.Get("/groups/{groupJid}/requests", groupHandler.GetRequestParticipants)
-			r.Post("/groups/{groupJid}/requests/approve", groupHandler.ApproveRequestParticipants)
-			r.Post("/groups/{groupJid}/requests/reject", groupHandler.RejectRequestParticipants)
-			r.Put("/groups/{groupJid}/settings/memberadd", groupHandler.SetMemberAddMode)
-
 			// Community
-			r.Post("/community/link", groupHandler.LinkGroup)
-			r.Post("/community/unlink", groupHandler.UnlinkGroup)
-			r.Get("/community/{communityJid}/subgroups", groupHandler.GetSubGroups)
-			r.Get("/community/{communityJid}/participants", groupHandler.GetLinkedParticipants)
+			r.With(scope(auth.ScopeGroupsWrite), groupWriteLimit).Post("/community/link", groupHandler.LinkGroup)
+			r.With(scope(auth.ScopeGroupsWrite), groupWriteLimit).Post("/community/unlink", groupHandler.UnlinkGroup)
+			r.With(scope(auth.ScopeGroupsRead)).Get("/community/{communityJid}/subgroups", groupHandler.GetSubGroups)
+			r.With(scope(auth.ScopeGroupsRead)).Get("/community/{communityJid}/participants", groupHandler.GetLinkedParticipants)
 
 			// Calls
-			r.Post("/calls/reject", callHandler.RejectCall)
+			r.With(scope(auth.ScopeCallsWrite)).Post("/calls/reject", callHandler.RejectCall)
 
 			// Webhook
 			r.Route("/webhook", func(r chi.Router) {
+				r.Use(scope(auth.ScopeWebhookManage))
 				r.Post("/", webhookHandler.SetWebhook)
 				r.Get("/", webhookHandler.GetWebhook)
 				r.Delete("/", webhookHandler.DeleteWebhook)
@@ -202,12 +279,78 @@ func New(provider core.Provider, logger zerolog.Logger, globalToken string, webh
 		})
 	})
 
+	// API keys (apenas token global)
+	r.Route("/apikeys", func(r chi.Router) {
+		r.Use(authMiddleware.Global)
+		r.Use(limit.Token)
+		r.Post("/", apiKeyHandler.Create)
+		r.Get("/", apiKeyHandler.List)
+		r.Delete("/{id}", apiKeyHandler.Delete)
+	})
+
+	// Tenants (apenas token global)
+	r.Route("/tenants", func(r chi.Router) {
+		r.Use(authMiddleware.Global)
+		r.Use(limit.Token)
+		r.Post("/", tenantHandler.Create)
+		r.Get("/", tenantHandler.List)
+		r.Put("/{id}", tenantHandler.Update)
+		r.Delete("/{id}", tenantHandler.Delete)
+	})
+
+	// Auditoria (apenas token global)
+	r.With(authMiddleware.Global, limit.Token).Get("/audit", auditHandler.List)
+
 	// Global webhook events endpoint
-	r.With(authMiddleware.Global).Get("/webhook/events", webhookHandler.GetSupportedEvents)
+	r.With(authMiddleware.Global, limit.Token).Get("/webhook/events", webhookHandler.GetSupportedEvents)
 
 	return r
 }
 
+// timeoutExceptStreams aplica middleware.Timeout exceto em rotas de streaming (SSE/WebSocket)
+func timeoutExceptStreams(d time.Duration) func(next http.Handler) http.Handler {
+	timeout := middleware.Timeout(d)
+	return func(next http.Handler) http.Handler {
+		limited := timeout(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/stream") {
+				next.ServeHTTP(w, r)
+				return
+			}
+			limited.ServeHTTP(w, r)
+		})
+	}
+}
+
+// traced exclui do tracing as rotas de health, metricas e swagger
+func traced(r *http.Request) bool {
+	return !strings.HasPrefix(r.URL.Path, "/health") && r.URL.Path != "/metrics" && !strings.HasPrefix(r.URL.Path, "/swagger")
+}
+
+// instrument registra contagem e duracao das requisicoes pelo padrao da rota (sem parametros)
+// e nomeia o span da requisicao pela rota
+func instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span := trace.SpanFromContext(r.Context())
+		span.SetName(r.Method + " " + route)
+		span.SetAttributes(attribute.String("http.route", route))
+		metrics.HTTPRequests.WithLabelValues(r.Method, route, strconv.Itoa(status)).Inc()
+		metrics.HTTPDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
+
 func requestLogger(logger zerolog.Logger) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
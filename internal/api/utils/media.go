@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"io"
@@ -9,8 +10,21 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"fiozap/internal/tracing"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+var tracer = tracing.Tracer("media")
+
+// downloadClient cliente do download de midia por URL (com span e trace context)
+var downloadClient = &http.Client{
+	Timeout:   60 * time.Second,
+	Transport: otelhttp.NewTransport(http.DefaultTransport),
+}
+
 // MediaResult resultado do processamento de midia
 type MediaResult struct {
 	Data     []byte
@@ -20,22 +34,33 @@ type MediaResult struct {
 
 // ProcessMedia processa midia de diferentes fontes: base64, URL ou form-data
 // Retorna os bytes da midia, mimetype detectado e nome do arquivo (se disponivel)
-func ProcessMedia(media string, providedMimeType string) (*MediaResult, error) {
+func ProcessMedia(ctx context.Context, media string, providedMimeType string) (result *MediaResult, err error) {
 	if media == "" {
 		return nil, fmt.Errorf("media is empty")
 	}
 
+	ctx, span := tracer.Start(ctx, "media.Process")
+	defer func() {
+		if result != nil {
+			span.SetAttributes(attribute.Int("fiozap.media.bytes", len(result.Data)), attribute.String("fiozap.media.mime", result.MimeType))
+		}
+		tracing.End(span, err)
+	}()
+
 	// Verifica se é uma URL (http:// ou https://)
 	if strings.HasPrefix(media, "http://") || strings.HasPrefix(media, "https://") {
-		return downloadFromURL(media, providedMimeType)
+		span.SetAttributes(attribute.String("fiozap.media.source", "url"))
+		return downloadFromURL(ctx, media, providedMimeType)
 	}
 
 	// Verifica se é data URL (data:mime/type;base64,...)
 	if strings.HasPrefix(media, "data:") {
+		span.SetAttributes(attribute.String("fiozap.media.source", "data_url"))
 		return decodeDataURL(media)
 	}
 
 	// Assume que é base64 puro
+	span.SetAttributes(attribute.String("fiozap.media.source", "base64"))
 	return decodeBase64(media, providedMimeType)
 }
 
@@ -66,12 +91,13 @@ func ProcessFormFile(r *http.Request, fieldName string) (*MediaResult, error) {
 }
 
 // downloadFromURL baixa midia de uma URL publica
-func downloadFromURL(url string, providedMimeType string) (*MediaResult, error) {
-	client := &http.Client{
-		Timeout: 60 * time.Second,
+func downloadFromURL(ctx context.Context, url string, providedMimeType string) (*MediaResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download from URL: %w", err)
 	}
 
-	resp, err := client.Get(url)
+	resp, err := downloadClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download from URL: %w", err)
 	}
@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"fiozap/internal/repository"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// bufferSize registros aguardando gravacao; cheio, os novos sao descartados (e logados)
+	bufferSize = 1024
+	// writeTimeout prazo da gravacao de um registro
+	writeTimeout = 5 * time.Second
+)
+
+// Writer grava a trilha de auditoria em segundo plano, fora do caminho da requisicao
+type Writer struct {
+	repo    repository.AuditRepository
+	log     zerolog.Logger
+	entries chan *repository.AuditModel
+	done    chan struct{}
+
+	// Drenagem no shutdown: registros na fila sao gravados ate o prazo, novos sao descartados
+	mu     sync.Mutex
+	closed bool
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewWriter cria o Writer e inicia a gravacao em segundo plano
+func NewWriter(repo repository.AuditRepository, logger zerolog.Logger) *Writer {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &Writer{
+		repo:    repo,
+		log:     logger.With().Str("component", "audit").Logger(),
+		entries: make(chan *repository.AuditModel, bufferSize),
+		done:    make(chan struct{}),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+	go w.run()
+	return w
+}
+
+// Record enfileira o registro sem bloquear
+func (w *Writer) Record(entry *repository.AuditModel) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		w.logDropped(entry, "writer closed")
+		return
+	}
+	select {
+	case w.entries <- entry:
+	default:
+		w.logDropped(entry, "queue full")
+	}
+}
+
+func (w *Writer) run() {
+	defer close(w.done)
+	for entry := range w.entries {
+		ctx, cancel := context.WithTimeout(w.ctx, writeTimeout)
+		if err := w.repo.Create(ctx, entry); err != nil {
+			w.log.Error().Err(err).
+				Str("actor", entry.ActorType).
+				Str("route", entry.Route).
+				Str("request_id", entry.RequestID.String).
+				Msg("Failed to write audit entry")
+		}
+		cancel()
+	}
+}
+
+// Shutdown para de aceitar registros e grava os enfileirados ate o prazo do ctx; depois disso
+// cancela a gravacao em andamento e descarta o restante
+func (w *Writer) Shutdown(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.entries)
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+		w.log.Info().Msg("Audit entries drained")
+		return nil
+	case <-ctx.Done():
+		w.cancel()
+		<-w.done
+		return fmt.Errorf("audit drain interrupted: %w", ctx.Err())
+	}
+}
+
+func (w *Writer) logDropped(entry *repository.AuditModel, reason string) {
+	w.log.Error().
+		Str("reason", reason).
+		Str("actor", entry.ActorType).
+		Str("actor_id", entry.ActorID.String).
+		Str("method", entry.Method).
+		Str("path", entry.Path).
+		Int("status", entry.Status).
+		Str("request_id", entry.RequestID.String).
+		Msg("Audit entry dropped")
+}
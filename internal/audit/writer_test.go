@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"fiozap/internal/repository"
+
+	"github.com/rs/zerolog"
+)
+
+type fakeAuditRepo struct {
+	mu      sync.Mutex
+	entries []*repository.AuditModel
+	block   chan struct{}
+}
+
+func (f *fakeAuditRepo) Create(ctx context.Context, entry *repository.AuditModel) error {
+	if f.block != nil {
+		select {
+		case <-f.block:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+func (f *fakeAuditRepo) List(context.Context, repository.AuditFilter) ([]*repository.AuditModel, int, error) {
+	return nil, 0, nil
+}
+
+func TestWriterDrainsOnShutdown(t *testing.T) {
+	repo := &fakeAuditRepo{}
+	w := NewWriter(repo, zerolog.Nop())
+	for i := 0; i < 10; i++ {
+		w.Record(&repository.AuditModel{Status: i})
+	}
+	if err := w.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if len(repo.entries) != 10 {
+		t.Fatalf("written = %d, want 10", len(repo.entries))
+	}
+
+	// Depois do shutdown o registro e descartado sem panic
+	w.Record(&repository.AuditModel{})
+	if err := w.Shutdown(context.Background()); err != nil {
+		t.Fatalf("second Shutdown: %v", err)
+	}
+}
+
+func TestWriterShutdownDeadline(t *testing.T) {
+	repo := &fakeAuditRepo{block: make(chan struct{})}
+	w := NewWriter(repo, zerolog.Nop())
+	w.Record(&repository.AuditModel{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := w.Shutdown(ctx); err == nil {
+		t.Fatal("Shutdown returned nil with a stuck write")
+	}
+}
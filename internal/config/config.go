@@ -2,6 +2,8 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -15,6 +17,44 @@ type Config struct {
 	LogFormat      string
 	WADebug        bool
 	GlobalAPIToken string
+	MetricsToken   string // protege /metrics (vazio = aberto)
+
+	// Proxies confiaveis (IPs/CIDRs): so deles valem X-Forwarded-For e X-Real-IP
+	TrustedProxies string
+
+	// Prazo total do graceful shutdown (HTTP, sessoes e webhooks)
+	ShutdownTimeout time.Duration
+
+	// Reconexao automatica
+	ReconnectConcurrency int
+	ReconnectBaseDelay   time.Duration
+	ReconnectMaxDelay    time.Duration
+
+	// Espera pela conexao ao reconectar sob demanda uma sessao ociosa
+	IdleWakeTimeout time.Duration
+
+	// Replicas: posse de sessoes por instancia
+	InstanceID         string
+	InstanceAddr       string
+	LeaseTTL           time.Duration
+	LeaseRenewInterval time.Duration
+
+	// Exibicao padrao em "Aparelhos conectados"
+	DeviceName     string
+	DevicePlatform string
+
+	// OpenTelemetry (exportador OTLP configurado pelas variaveis OTEL_* padrao)
+	TracingEnabled     bool
+	TracingServiceName string
+
+	// Rate limiting (token bucket no formato N/unidade[:rajada]; "0" desativa)
+	RateLimitEnabled      bool
+	RateLimitStore        string // memory ou redis (usa REDIS_URL)
+	RateLimitToken        string
+	RateLimitSession      string
+	RateLimitSend         string
+	RateLimitContactCheck string
+	RateLimitGroupWrite   string
 
 	// WhatsApp Cloud API (Meta)
 	CloudAPIPhoneNumberID string
@@ -33,6 +73,36 @@ func Load() *Config {
 		LogFormat:      getEnv("LOG_FORMAT", "console"),
 		WADebug:        getEnv("WA_DEBUG", "false") == "true",
 		GlobalAPIToken: getEnv("GLOBAL_API_TOKEN", ""),
+		MetricsToken:   getEnv("METRICS_TOKEN", ""),
+
+		TrustedProxies: getEnv("TRUSTED_PROXIES", ""),
+
+		ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+
+		ReconnectConcurrency: getEnvInt("RECONNECT_CONCURRENCY", 5),
+		ReconnectBaseDelay:   getEnvDuration("RECONNECT_BASE_DELAY", 2*time.Second),
+		ReconnectMaxDelay:    getEnvDuration("RECONNECT_MAX_DELAY", 5*time.Minute),
+
+		IdleWakeTimeout: getEnvDuration("IDLE_WAKE_TIMEOUT", 20*time.Second),
+
+		InstanceID:         getEnv("INSTANCE_ID", hostname()),
+		InstanceAddr:       getEnv("INSTANCE_ADDR", ""),
+		LeaseTTL:           getEnvDuration("LEASE_TTL", 30*time.Second),
+		LeaseRenewInterval: getEnvDuration("LEASE_RENEW_INTERVAL", 10*time.Second),
+
+		DeviceName:     getEnv("DEVICE_NAME", "FioZap"),
+		DevicePlatform: getEnv("DEVICE_PLATFORM", "chrome"),
+
+		TracingEnabled:     getEnv("TRACING_ENABLED", "false") == "true",
+		TracingServiceName: getEnv("OTEL_SERVICE_NAME", "fiozap"),
+
+		RateLimitEnabled:      getEnv("RATE_LIMIT_ENABLED", "false") == "true",
+		RateLimitStore:        getEnv("RATE_LIMIT_STORE", "memory"),
+		RateLimitToken:        getEnv("RATE_LIMIT_TOKEN", "50/s:100"),
+		RateLimitSession:      getEnv("RATE_LIMIT_SESSION", "20/s:40"),
+		RateLimitSend:         getEnv("RATE_LIMIT_SEND", "120/m:20"),
+		RateLimitContactCheck: getEnv("RATE_LIMIT_CONTACT_CHECK", "30/m:10"),
+		RateLimitGroupWrite:   getEnv("RATE_LIMIT_GROUP_WRITE", "20/m:5"),
 
 		CloudAPIPhoneNumberID: getEnv("CLOUD_API_PHONE_NUMBER_ID", ""),
 		CloudAPIAccessToken:   getEnv("CLOUD_API_ACCESS_TOKEN", ""),
@@ -45,3 +115,27 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// hostname identifica a replica por padrao (unico por container/pod)
+func hostname() string {
+	name, _ := os.Hostname()
+	return name
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+	}
+	return defaultValue
+}
@@ -8,27 +8,52 @@ import (
 // Provider interface para provedores de mensageria (whatsmeow, cloudapi, etc)
 type Provider interface {
 	// Session management
-	CreateSession(ctx context.Context, name string) (Session, error)
+	CreateSession(ctx context.Context, name string, opts CreateSessionOptions) (Session, *SessionToken, error)
 	GetSession(name string) (Session, error)
 	ListSessions() []Session
+	SearchSessions(ctx context.Context, query SessionQuery) ([]Session, int, error)
+	SetSessionLabels(ctx context.Context, name string, metadata map[string]string, tags []string) error
+	SessionOwner(ctx context.Context, name string) (*SessionOwner, error)
+	ExportSession(ctx context.Context, name, passphrase string, detach bool) (*SessionArchive, error)
+	ImportSession(ctx context.Context, archive []byte, passphrase string, force bool) (Session, error)
 	DeleteSession(ctx context.Context, name string) error
 	Connect(ctx context.Context, name string) (Session, error)
 	Disconnect(name string) error
 	Logout(ctx context.Context, name string) error
+	PairPhone(ctx context.Context, name, phone string) (*PairingCode, error)
+	SubscribeLogin(name string) (<-chan LoginEvent, func(), error)
+	GetSessionHistory(ctx context.Context, name string, limit int) ([]SessionStatusChange, error)
+	RotateToken(ctx context.Context, name string, grace time.Duration, expiresAt time.Time) (*SessionToken, error)
+	SetProxy(ctx context.Context, name, proxy string) error
+	SetIdleTimeout(ctx context.Context, name string, timeout time.Duration) error
+	GetSessionHealth(ctx context.Context, name string) (*SessionHealth, error)
+	Ready() bool
 
 	// Messages
 	SendText(ctx context.Context, session, to, text string) (*MessageResponse, error)
-	SendImage(ctx context.Context, session, to string, data []byte, caption, mimeType string) (*MessageResponse, error)
-	SendVideo(ctx context.Context, session, to string, data []byte, caption, mimeType string) (*MessageResponse, error)
-	SendAudio(ctx context.Context, session, to string, data []byte, mimeType string) (*MessageResponse, error)
+	SendImage(ctx context.Context, session, to string, data []byte, caption, mimeType string, viewOnce bool) (*MessageResponse, error)
+	SendVideo(ctx context.Context, session, to string, data []byte, caption, mimeType string, viewOnce bool) (*MessageResponse, error)
+	SendAudio(ctx context.Context, session, to string, data []byte, mimeType string, viewOnce bool) (*MessageResponse, error)
 	SendDocument(ctx context.Context, session, to string, data []byte, filename, mimeType string) (*MessageResponse, error)
 	SendSticker(ctx context.Context, session, to string, data []byte, mimeType string) (*MessageResponse, error)
 	SendLocation(ctx context.Context, session, to string, lat, lng float64, name, address string) (*MessageResponse, error)
 	SendContact(ctx context.Context, session, to, name, vcard string) (*MessageResponse, error)
 	SendPoll(ctx context.Context, session, to, question string, options []string, multiSelect bool) (*MessageResponse, error)
 	SendReaction(ctx context.Context, session, to, messageID, emoji string) (*MessageResponse, error)
-	EditMessage(ctx context.Context, session, chat, messageID, newText string) (*MessageResponse, error)
-	RevokeMessage(ctx context.Context, session, chat, messageID string) (*MessageResponse, error)
+	EditMessage(ctx context.Context, session, chat, messageID, newText, mediaType string) (*MessageResponse, error)
+	RevokeMessage(ctx context.Context, session, chat, sender, messageID string) (*MessageResponse, error)
+	PinMessage(ctx context.Context, session, chat, sender, messageID string, pin bool, duration time.Duration) (*MessageResponse, error)
+	StarMessage(ctx context.Context, session, chat, sender, messageID string, star bool) error
+	KeepMessage(ctx context.Context, session, chat, sender, messageID string, keep bool) (*MessageResponse, error)
+
+	// Polls
+	GetPollResults(ctx context.Context, session, messageID string) (*PollResults, error)
+
+	// Status (stories)
+	SendTextStatus(ctx context.Context, session, text string, backgroundARGB, textARGB uint32, font int32, audience *StatusAudience) (*MessageResponse, error)
+	SendImageStatus(ctx context.Context, session string, data []byte, caption, mimeType string, audience *StatusAudience) (*MessageResponse, error)
+	SendVideoStatus(ctx context.Context, session string, data []byte, caption, mimeType string, audience *StatusAudience) (*MessageResponse, error)
+	ListStatuses(ctx context.Context, session, sender string, limit int) ([]StatusUpdate, error)
 
 	// Chat
 	MarkRead(ctx context.Context, session, chatJID string, messageIDs []string) error
@@ -5,12 +5,133 @@ import "time"
 // Session representa uma sessao de mensageria
 type Session interface {
 	GetName() string
-	GetToken() string
+	GetTenantID() string
+	GetProxy() string
+	GetMetadata() map[string]string
+	GetTags() []string
+	GetDeviceName() string
+	GetDevicePlatform() string
+	GetIdleTimeout() time.Duration
+	CheckToken(token string) bool
 	GetJID() string
 	GetPhone() string
 	GetPushName() string
 	GetQRCode() string
 	IsConnected() bool
+	GetStatus() SessionStatus
+}
+
+// SessionStatus estado do ciclo de vida da sessao
+type SessionStatus string
+
+const (
+	SessionCreated      SessionStatus = "created"      // nunca pareada
+	SessionPairing      SessionStatus = "pairing"      // aguardando QR ou codigo
+	SessionConnecting   SessionStatus = "connecting"   // pareada, abrindo conexao
+	SessionConnected    SessionStatus = "connected"    // online
+	SessionDisconnected SessionStatus = "disconnected" // desconectada manualmente ou apos reinicio
+	SessionReconnecting SessionStatus = "reconnecting" // conexao caiu, reconectando
+	SessionLoggedOut    SessionStatus = "logged_out"   // dispositivo removido, requer novo pareamento
+	SessionBanned       SessionStatus = "banned"       // banimento temporario
+	SessionFailed       SessionStatus = "failed"       // falha de conexao ou login
+	SessionIdle         SessionStatus = "idle"         // desconectada por inatividade, reconecta sob demanda
+)
+
+// SessionStatusChange transicao de estado da sessao
+type SessionStatusChange struct {
+	From   SessionStatus
+	To     SessionStatus
+	Reason string
+	At     time.Time
+}
+
+// CreateSessionOptions parametros opcionais de criacao de sessao
+type CreateSessionOptions struct {
+	TokenExpiresAt time.Time // zero = sem expiracao
+	TenantID       string    // vazio = sessao do super-admin
+	MaxSessions    int       // cota de sessoes do tenant (0 = ilimitado)
+	Proxy          string    // http://, https:// ou socks5:// (vazio = conexao direta)
+	Metadata       map[string]string
+	Tags           []string
+	DeviceName     string        // nome em "Aparelhos conectados" (vazio = padrao global)
+	DevicePlatform string        // chrome, firefox, safari, edge, opera ou desktop (vazio = padrao global)
+	IdleTimeout    time.Duration // desconecta apos esse tempo sem uso da API (0 = nunca)
+}
+
+// SessionArchive sessao exportada e cifrada com a senha informada
+type SessionArchive struct {
+	Data       []byte
+	ExportedAt time.Time
+	Detached   bool // sessao desligada na origem
+}
+
+// SessionHealth diagnostico de conectividade da sessao
+type SessionHealth struct {
+	Name                 string
+	Status               SessionStatus
+	Healthy              bool // websocket aberto, logada e no estado connected
+	Owned                bool // atendida por esta instancia
+	WebsocketConnected   bool
+	LoggedIn             bool
+	LastConnectedAt      time.Time
+	LastEventAt          time.Time
+	LastKeepAliveTimeout time.Time
+	UploadedPreKeys      int // prekeys enviados ao servidor e ainda nao consumidos
+	LastSendError        string
+	LastSendErrorAt      time.Time
+}
+
+// SessionOwner instancia que atende a sessao (posse entre replicas)
+type SessionOwner struct {
+	InstanceID string
+	Address    string // URL interna da instancia (vazio = nao anunciada)
+	Local      bool   // esta instancia atende a sessao
+}
+
+// SessionQuery filtros, ordenacao e paginacao da listagem de sessoes (campos vazios nao filtram)
+type SessionQuery struct {
+	Status     SessionStatus
+	Tag        string
+	Phone      string // prefixo
+	NamePrefix string
+	TenantID   string
+	Names      []string // restringe aos nomes (nil = todos)
+	SortBy     string   // name, createdAt, updatedAt, status ou phone
+	Desc       bool
+	Limit      int
+	Offset     int
+}
+
+// SessionToken token de sessao em texto puro, exibido apenas na criacao ou rotacao
+type SessionToken struct {
+	Token             string
+	ExpiresAt         time.Time
+	PreviousExpiresAt time.Time // fim da carencia do token anterior (zero = revogado na hora)
+}
+
+// PairingCode codigo de pareamento por numero de telefone
+type PairingCode struct {
+	Code      string
+	ExpiresAt time.Time
+}
+
+// Eventos do fluxo de login emitidos por SubscribeLogin
+const (
+	LoginEventCode        = "code"
+	LoginEventPairingCode = "pairing_code"
+	LoginEventTimeout     = "timeout"
+	LoginEventSuccess     = "success"
+	LoginEventConnected   = "connected"
+	LoginEventError       = "error"
+)
+
+// LoginEvent evento do fluxo de login (QR, codigo de pareamento, resultado)
+type LoginEvent struct {
+	Event     string
+	Code      string
+	JID       string
+	Error     string
+	ExpiresAt time.Time
 }
 
 // MessageResponse resposta de envio de mensagem
@@ -57,3 +178,43 @@ type GroupParticipant struct {
 	IsAdmin      bool
 	IsSuperAdmin bool
 }
+
+// PollResults contagem atual de votos de uma enquete
+type PollResults struct {
+	MessageID   string
+	ChatJID     string
+	Question    string
+	Options     []PollOptionResult
+	TotalVoters int
+}
+
+// PollOptionResult votos de uma opcao da enquete
+type PollOptionResult struct {
+	Name   string
+	Count  int
+	Voters []string
+}
+
+// Tipos de audiencia de um status publicado
+const (
+	StatusAudienceContacts       = "contacts"        // todos os contatos
+	StatusAudienceContactsExcept = "contacts_except" // contatos, exceto os da lista
+	StatusAudienceOnly           = "only"            // apenas os da lista
+)
+
+// StatusAudience destinatarios de um status. nil segue a privacidade de status da conta; o
+// provider wameow ainda recusa audiencia propria (o whatsmeow nao tem API publica para isso)
+type StatusAudience struct {
+	Type   string
+	Phones []string
+}
+
+// StatusUpdate status (story) recebido de um contato
+type StatusUpdate struct {
+	ID        string
+	SenderJID string
+	Type      string
+	Body      string
+	MimeType  string
+	Timestamp time.Time
+}
@@ -13,6 +13,45 @@ import (
 //go:embed upgrades/001_create_sessions.sql
 var migration001 string
 
+//go:embed upgrades/002_create_polls.sql
+var migration002 string
+
+//go:embed upgrades/003_create_statuses.sql
+var migration003 string
+
+//go:embed upgrades/004_session_status.sql
+var migration004 string
+
+//go:embed upgrades/005_hash_session_tokens.sql
+var migration005 string
+
+//go:embed upgrades/006_create_api_keys.sql
+var migration006 string
+
+//go:embed upgrades/007_create_tenants.sql
+var migration007 string
+
+//go:embed upgrades/008_session_proxy.sql
+var migration008 string
+
+//go:embed upgrades/009_session_metadata.sql
+var migration009 string
+
+//go:embed upgrades/010_session_device_props.sql
+var migration010 string
+
+//go:embed upgrades/011_session_leases.sql
+var migration011 string
+
+//go:embed upgrades/012_session_reconnect_on_boot.sql
+var migration012 string
+
+//go:embed upgrades/013_session_idle_timeout.sql
+var migration013 string
+
+//go:embed upgrades/014_create_audit_log.sql
+var migration014 string
+
 type Database struct {
 	DB        *sql.DB
 	Container *sqlstore.Container
@@ -52,6 +91,19 @@ func runMigrations(db *sql.DB) error {
 		sql     string
 	}{
 		{"001_create_sessions", migration001},
+		{"002_create_polls", migration002},
+		{"003_create_statuses", migration003},
+		{"004_session_status", migration004},
+		{"005_hash_session_tokens", migration005},
+		{"006_create_api_keys", migration006},
+		{"007_create_tenants", migration007},
+		{"008_session_proxy", migration008},
+		{"009_session_metadata", migration009},
+		{"010_session_device_props", migration010},
+		{"011_session_leases", migration011},
+		{"012_session_reconnect_on_boot", migration012},
+		{"013_session_idle_timeout", migration013},
+		{"014_create_audit_log", migration014},
 	}
 
 	for _, m := range migrations {
@@ -0,0 +1,24 @@
+-- 002_create_polls.sql
+-- Enquetes e votos decifrados
+
+CREATE TABLE IF NOT EXISTS "polls" (
+    "sessionId" VARCHAR(255) NOT NULL REFERENCES "sessions"("id") ON DELETE CASCADE,
+    "messageId" VARCHAR(255) NOT NULL,
+    "chatJid" VARCHAR(255) NOT NULL,
+    "senderJid" VARCHAR(255),
+    "question" TEXT NOT NULL,
+    "options" JSONB NOT NULL,
+    "selectableCount" INTEGER NOT NULL DEFAULT 0,
+    "createdAt" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("sessionId", "messageId")
+);
+
+CREATE TABLE IF NOT EXISTS "poll_votes" (
+    "sessionId" VARCHAR(255) NOT NULL,
+    "messageId" VARCHAR(255) NOT NULL,
+    "voterJid" VARCHAR(255) NOT NULL,
+    "options" JSONB NOT NULL,
+    "votedAt" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("sessionId", "messageId", "voterJid"),
+    FOREIGN KEY ("sessionId", "messageId") REFERENCES "polls"("sessionId", "messageId") ON DELETE CASCADE
+);
@@ -0,0 +1,17 @@
+-- 003_create_statuses.sql
+-- Status (stories) recebidos via status@broadcast
+
+CREATE TABLE IF NOT EXISTS "statuses" (
+    "sessionId" VARCHAR(255) NOT NULL REFERENCES "sessions"("id") ON DELETE CASCADE,
+    "messageId" VARCHAR(255) NOT NULL,
+    "senderJid" VARCHAR(255) NOT NULL,
+    "type" VARCHAR(50) NOT NULL,
+    "body" TEXT,
+    "mimeType" VARCHAR(255),
+    "timestamp" TIMESTAMP WITH TIME ZONE NOT NULL,
+    "createdAt" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY ("sessionId", "messageId")
+);
+
+CREATE INDEX IF NOT EXISTS "idx_statuses_timestamp" ON "statuses"("sessionId", "timestamp" DESC);
+CREATE INDEX IF NOT EXISTS "idx_statuses_sender" ON "statuses"("sessionId", "senderJid");
@@ -0,0 +1,20 @@
+-- 004_session_status.sql
+-- Estado explicito da sessao e historico de transicoes
+
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "status" VARCHAR(32) NOT NULL DEFAULT 'created';
+
+-- Sessoes existentes: pareadas ficam desconectadas ate a proxima conexao
+UPDATE "sessions" SET "status" = 'disconnected' WHERE "jid" IS NOT NULL AND "jid" <> '';
+
+CREATE INDEX IF NOT EXISTS "idx_sessions_status" ON "sessions"("status");
+
+CREATE TABLE IF NOT EXISTS "session_status_history" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "sessionId" VARCHAR(255) NOT NULL REFERENCES "sessions"("id") ON DELETE CASCADE,
+    "fromStatus" VARCHAR(32),
+    "toStatus" VARCHAR(32) NOT NULL,
+    "reason" TEXT,
+    "createdAt" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "idx_session_status_history_session" ON "session_status_history"("sessionId", "createdAt" DESC);
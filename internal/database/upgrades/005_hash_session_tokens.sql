@@ -0,0 +1,17 @@
+-- 005_hash_session_tokens.sql
+-- Tokens de sessao passam a ser guardados apenas como hash SHA-256,
+-- com expiracao opcional e token anterior valido durante a carencia da rotacao
+
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "tokenHash" VARCHAR(64);
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "tokenExpiresAt" TIMESTAMP WITH TIME ZONE;
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "previousTokenHash" VARCHAR(64);
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "previousTokenExpiresAt" TIMESTAMP WITH TIME ZONE;
+
+UPDATE "sessions" SET "tokenHash" = encode(sha256(convert_to("token", 'UTF8')), 'hex') WHERE "tokenHash" IS NULL;
+
+ALTER TABLE "sessions" ALTER COLUMN "tokenHash" SET NOT NULL;
+
+DROP INDEX IF EXISTS "idx_sessions_token";
+ALTER TABLE "sessions" DROP COLUMN IF EXISTS "token";
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_sessions_token_hash" ON "sessions"("tokenHash");
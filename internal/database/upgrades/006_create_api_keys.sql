@@ -0,0 +1,15 @@
+-- 006_create_api_keys.sql
+-- API keys com escopos, allowlist de sessoes e de IPs (apenas hash SHA-256 da chave)
+
+CREATE TABLE IF NOT EXISTS "api_keys" (
+    "id" VARCHAR(255) PRIMARY KEY,
+    "name" VARCHAR(255) NOT NULL,
+    "prefix" VARCHAR(32) NOT NULL,
+    "keyHash" VARCHAR(64) NOT NULL UNIQUE,
+    "scopes" JSONB NOT NULL,
+    "sessions" JSONB NOT NULL DEFAULT '[]',
+    "allowedIps" JSONB NOT NULL DEFAULT '[]',
+    "expiresAt" TIMESTAMP WITH TIME ZONE,
+    "lastUsedAt" TIMESTAMP WITH TIME ZONE,
+    "createdAt" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,22 @@
+-- 007_create_tenants.sql
+-- Tenants com token de admin proprio, cotas e isolamento de sessoes
+
+CREATE TABLE IF NOT EXISTS "tenants" (
+    "id" VARCHAR(255) PRIMARY KEY,
+    "name" VARCHAR(255) NOT NULL UNIQUE,
+    "tokenHash" VARCHAR(64) NOT NULL UNIQUE,
+    "maxSessions" INTEGER NOT NULL DEFAULT 0,
+    "maxMessagesPerDay" INTEGER NOT NULL DEFAULT 0,
+    "createdAt" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Sessoes sem tenant pertencem ao super-admin (token global)
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "tenantId" VARCHAR(255) REFERENCES "tenants"("id") ON DELETE RESTRICT;
+CREATE INDEX IF NOT EXISTS "idx_sessions_tenant" ON "sessions"("tenantId");
+
+CREATE TABLE IF NOT EXISTS "tenant_message_usage" (
+    "tenantId" VARCHAR(255) NOT NULL REFERENCES "tenants"("id") ON DELETE CASCADE,
+    "day" DATE NOT NULL,
+    "count" INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY ("tenantId", "day")
+);
@@ -0,0 +1,4 @@
+-- 008_session_proxy.sql
+-- Proxy HTTP/SOCKS5 por sessao (websocket e midia)
+
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "proxy" TEXT;
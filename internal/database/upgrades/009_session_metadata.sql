@@ -0,0 +1,10 @@
+-- 009_session_metadata.sql
+-- Metadados chave/valor e tags por sessao, com indices para busca e ordenacao
+
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "metadata" JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "tags" JSONB NOT NULL DEFAULT '[]';
+
+CREATE INDEX IF NOT EXISTS "idx_sessions_tags" ON "sessions" USING GIN ("tags");
+CREATE INDEX IF NOT EXISTS "idx_sessions_status" ON "sessions"("status");
+CREATE INDEX IF NOT EXISTS "idx_sessions_phone" ON "sessions"("phone");
+CREATE INDEX IF NOT EXISTS "idx_sessions_created" ON "sessions"("createdAt");
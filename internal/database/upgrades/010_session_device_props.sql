@@ -0,0 +1,5 @@
+-- 010_session_device_props.sql
+-- Nome e plataforma exibidos em "Aparelhos conectados" no celular
+
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "deviceName" VARCHAR(255);
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "devicePlatform" VARCHAR(32);
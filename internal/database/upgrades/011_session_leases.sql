@@ -0,0 +1,12 @@
+-- 011_session_leases.sql
+-- Posse de sessao por instancia (lease com heartbeat) para rodar varias replicas
+
+CREATE TABLE IF NOT EXISTS "session_leases" (
+    "sessionId" VARCHAR(255) PRIMARY KEY REFERENCES "sessions"("id") ON DELETE CASCADE,
+    "ownerId" VARCHAR(255) NOT NULL,
+    "ownerAddr" VARCHAR(255),
+    "acquiredAt" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    "expiresAt" TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "idx_session_leases_owner" ON "session_leases"("ownerId");
@@ -0,0 +1,4 @@
+-- 012_session_reconnect_on_boot.sql
+-- Sessoes desligadas pelo graceful shutdown: desconectadas, mas reconectam no proximo boot
+
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "reconnectOnBoot" BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,4 @@
+-- 013_session_idle_timeout.sql
+-- Politica de inatividade: desconecta apos N segundos sem uso da API (0 = nunca)
+
+ALTER TABLE "sessions" ADD COLUMN IF NOT EXISTS "idleTimeout" INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,33 @@
+-- 014_create_audit_log.sql
+-- Trilha de auditoria das acoes que alteram estado (somente insercao)
+
+CREATE TABLE IF NOT EXISTS "audit_log" (
+    "id" BIGSERIAL PRIMARY KEY,
+    "actorType" VARCHAR(20) NOT NULL,
+    "actorId" VARCHAR(255),
+    "ip" VARCHAR(64),
+    "requestId" VARCHAR(255),
+    "method" VARCHAR(10) NOT NULL,
+    "route" VARCHAR(255) NOT NULL,
+    "path" TEXT NOT NULL,
+    "session" VARCHAR(255),
+    "status" INTEGER NOT NULL,
+    "outcome" VARCHAR(20) NOT NULL,
+    "createdAt" TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS "idx_audit_log_created" ON "audit_log"("createdAt" DESC);
+CREATE INDEX IF NOT EXISTS "idx_audit_log_session" ON "audit_log"("session", "createdAt" DESC);
+CREATE INDEX IF NOT EXISTS "idx_audit_log_actor" ON "audit_log"("actorType", "actorId", "createdAt" DESC);
+
+-- Registros nao podem ser alterados nem removidos
+CREATE OR REPLACE FUNCTION "audit_log_append_only"() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS "audit_log_no_update" ON "audit_log";
+CREATE TRIGGER "audit_log_no_update"
+    BEFORE UPDATE OR DELETE ON "audit_log"
+    FOR EACH ROW EXECUTE FUNCTION "audit_log_append_only"();
@@ -13,9 +13,17 @@ import (
 	"sync"
 	"time"
 
+	"fiozap/internal/metrics"
+	"fiozap/internal/tracing"
+
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = tracing.Tracer("webhook")
+
 // internalConfig armazena configuracao interna do webhook
 type internalConfig struct {
 	URL     string
@@ -31,18 +39,54 @@ type Dispatcher struct {
 	configsMu  sync.RWMutex
 	retryCount int
 	retryDelay time.Duration
+
+	// Drenagem no shutdown: envios em andamento terminam ate o prazo, novos sao descartados
+	inflight sync.WaitGroup
+	drainMu  sync.Mutex
+	closed   bool
+	ctx      context.Context
+	cancel   context.CancelFunc
 }
 
 // NewDispatcher cria um novo dispatcher de webhooks
 func NewDispatcher(logger zerolog.Logger) *Dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &Dispatcher{
 		client: &http.Client{
 			Timeout: 30 * time.Second,
+			// Span por tentativa e header traceparent (W3C) no request
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
 		},
 		logger:     logger.With().Str("component", "webhook").Logger(),
 		configs:    make(map[string]*internalConfig),
 		retryCount: 3,
 		retryDelay: 1 * time.Second,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Shutdown para de aceitar eventos e aguarda os envios em andamento (com retentativas)
+// ate o prazo do ctx; depois disso cancela os que restarem
+func (d *Dispatcher) Shutdown(ctx context.Context) error {
+	d.drainMu.Lock()
+	d.closed = true
+	d.drainMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		d.logger.Info().Msg("Webhooks drained")
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		<-done
+		return fmt.Errorf("webhook drain interrupted: %w", ctx.Err())
 	}
 }
 
@@ -85,6 +129,35 @@ func (d *Dispatcher) GetConfig(sessionID string) *Config {
 	}
 }
 
+// ExportConfig retorna a configuracao com a chave HMAC (nil se a sessao nao tem webhook)
+func (d *Dispatcher) ExportConfig(sessionID string) *ExportedConfig {
+	d.configsMu.RLock()
+	defer d.configsMu.RUnlock()
+
+	cfg, exists := d.configs[sessionID]
+	if !exists {
+		return nil
+	}
+	return &ExportedConfig{
+		URL:     cfg.URL,
+		Events:  append([]EventType{}, cfg.Events...),
+		HMACKey: cfg.HMACKey,
+	}
+}
+
+// ImportConfig restaura uma configuracao exportada, substituindo a atual
+func (d *Dispatcher) ImportConfig(sessionID string, cfg *ExportedConfig) {
+	d.configsMu.Lock()
+	defer d.configsMu.Unlock()
+
+	d.configs[sessionID] = &internalConfig{
+		URL:     cfg.URL,
+		Events:  cfg.Events,
+		HMACKey: cfg.HMACKey,
+	}
+	d.logger.Info().Str("session", sessionID).Str("url", cfg.URL).Msg("Webhook config imported")
+}
+
 // RemoveConfig remove a configuracao de webhook de uma sessao
 func (d *Dispatcher) RemoveConfig(sessionID string) {
 	d.configsMu.Lock()
@@ -149,7 +222,22 @@ func (d *Dispatcher) Dispatch(ctx context.Context, sessionID string, eventType E
 		Event:     rawEvent,
 	}
 
-	go d.sendWithRetry(ctx, cfg, event)
+	d.drainMu.Lock()
+	defer d.drainMu.Unlock()
+	if d.closed {
+		d.logger.Warn().
+			Str("session", sessionID).
+			Str("event", string(eventType)).
+			Msg("Dispatcher shut down, dropping webhook")
+		metrics.WebhookDeliveries.WithLabelValues("dropped").Inc()
+		return
+	}
+
+	d.inflight.Add(1)
+	go func() {
+		defer d.inflight.Done()
+		d.sendWithRetry(ctx, cfg, event)
+	}()
 }
 
 // isSubscribed verifica se o evento esta na lista de eventos subscritos
@@ -168,15 +256,42 @@ func (d *Dispatcher) isSubscribed(cfg *internalConfig, eventType EventType) bool
 
 // sendWithRetry envia o webhook com retentativas
 func (d *Dispatcher) sendWithRetry(ctx context.Context, cfg *internalConfig, event Event) {
+	// Cancelado tambem quando o prazo de drenagem do shutdown acaba
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(d.ctx, cancel)
+	defer stop()
+
+	ctx, span := tracer.Start(ctx, "webhook.Deliver", trace.WithAttributes(
+		attribute.String("fiozap.session", event.SessionID),
+		attribute.String("fiozap.webhook.event", string(event.Type)),
+	))
 	var lastErr error
+	defer func() { tracing.End(span, lastErr) }()
 
 	for attempt := 0; attempt < d.retryCount; attempt++ {
 		if attempt > 0 {
-			time.Sleep(d.retryDelay * time.Duration(attempt))
+			select {
+			case <-time.After(d.retryDelay * time.Duration(attempt)):
+			case <-ctx.Done():
+				d.logger.Error().
+					Err(ctx.Err()).
+					Str("url", cfg.URL).
+					Str("session", event.SessionID).
+					Str("event", string(event.Type)).
+					Msg("Webhook aborted before retry")
+				metrics.WebhookDeliveries.WithLabelValues("aborted").Inc()
+				return
+			}
 		}
 
+		start := time.Now()
 		err := d.send(ctx, cfg, event)
+		metrics.WebhookDuration.WithLabelValues(metrics.Result(err)).Observe(time.Since(start).Seconds())
 		if err == nil {
+			metrics.WebhookDeliveries.WithLabelValues("delivered").Inc()
+			span.SetAttributes(attribute.Int("fiozap.webhook.attempts", attempt+1))
+			lastErr = nil
 			return
 		}
 
@@ -195,6 +310,7 @@ func (d *Dispatcher) sendWithRetry(ctx context.Context, cfg *internalConfig, eve
 		Str("session", event.SessionID).
 		Str("event", string(event.Type)).
 		Msg("Webhook failed after all retries")
+	metrics.WebhookDeliveries.WithLabelValues("failed").Inc()
 }
 
 // send envia o webhook
@@ -0,0 +1,45 @@
+package webhook
+
+import "time"
+
+// PollVotePayload voto de enquete decifrado e normalizado
+type PollVotePayload struct {
+	PollID          string    `json:"pollId"`
+	Chat            string    `json:"chat"`
+	Voter           string    `json:"voter"`
+	SelectedOptions []string  `json:"selectedOptions"`
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+// StatusPayload status (story) recebido via status@broadcast
+type StatusPayload struct {
+	ID        string    `json:"id"`
+	Sender    string    `json:"sender"`
+	Type      string    `json:"type"`
+	Body      string    `json:"body,omitempty"`
+	MimeType  string    `json:"mimeType,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// PairingCodePayload codigo de pareamento gerado para login sem QR
+type PairingCodePayload struct {
+	Code      string    `json:"code"`
+	Phone     string    `json:"phone"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// SessionStatusPayload transicao de estado da sessao
+type SessionStatusPayload struct {
+	Status         string    `json:"status"`
+	PreviousStatus string    `json:"previousStatus,omitempty"`
+	Reason         string    `json:"reason,omitempty"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// ReconnectPayload tentativa do supervisor de reconexao (scheduled, connected ou stopped)
+type ReconnectPayload struct {
+	Attempt       int        `json:"attempt"`
+	Result        string     `json:"result"`
+	Reason        string     `json:"reason,omitempty"`
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
+}
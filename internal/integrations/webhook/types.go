@@ -11,6 +11,8 @@ const (
 	EventReceipt              EventType = "Receipt"
 	EventMediaRetry           EventType = "MediaRetry"
 	EventReadReceipt          EventType = "ReadReceipt"
+	EventPollVote             EventType = "PollVote"
+	EventStatus               EventType = "Status"
 
 	// Groups and Contacts
 	EventGroupInfo       EventType = "GroupInfo"
@@ -33,7 +35,10 @@ const (
 	EventStreamReplaced    EventType = "StreamReplaced"
 	EventPairSuccess       EventType = "PairSuccess"
 	EventPairError         EventType = "PairError"
+	EventPairingCode       EventType = "PairingCode"
 	EventQR                EventType = "QR"
+	EventSessionStatus     EventType = "SessionStatus"
+	EventReconnect         EventType = "Reconnect"
 
 	// Privacy and Settings
 	EventPrivacySettings EventType = "PrivacySettings"
@@ -88,6 +93,13 @@ type Config struct {
 	HMACKeySet bool        `json:"hmacKeySet"`
 }
 
+// ExportedConfig configuracao completa do webhook, inclusive a chave HMAC (exportacao de sessao)
+type ExportedConfig struct {
+	URL     string      `json:"url"`
+	Events  []EventType `json:"events"`
+	HMACKey string      `json:"hmacKey,omitempty"`
+}
+
 // SupportedEvents retorna lista de tipos de eventos suportados
 func SupportedEvents() []EventType {
 	return []EventType{
@@ -96,6 +108,8 @@ func SupportedEvents() []EventType {
 		EventReceipt,
 		EventMediaRetry,
 		EventReadReceipt,
+		EventPollVote,
+		EventStatus,
 		EventGroupInfo,
 		EventJoinedGroup,
 		EventPicture,
@@ -114,7 +128,10 @@ func SupportedEvents() []EventType {
 		EventStreamReplaced,
 		EventPairSuccess,
 		EventPairError,
+		EventPairingCode,
 		EventQR,
+		EventSessionStatus,
+		EventReconnect,
 		EventPrivacySettings,
 		EventPushNameSetting,
 		EventUserAbout,
@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "fiozap"
+
+// Registry registro das metricas expostas em /metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTP
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Requisicoes HTTP por rota, metodo e status.",
+	}, []string{"method", "route", "status"})
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Duracao das requisicoes HTTP por rota e metodo.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	RateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_total",
+		Help:      "Requisicoes recusadas pelo rate limiting por escopo (token, session ou classe de rota).",
+	}, []string{"scope"})
+
+	// Mensagens e midia
+	MessagesSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_sent_total",
+		Help:      "Mensagens enviadas por tipo e resultado (success ou error). Por sessao: fiozap_session_messages_sent_total.",
+	}, []string{"type", "result"})
+	UploadBytes = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "media_upload_bytes",
+		Help:      "Tamanho das midias enviadas ao WhatsApp.",
+		Buckets:   prometheus.ExponentialBuckets(16*1024, 4, 8), // 16KB a 256MB
+	}, []string{"type"})
+	UploadDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "media_upload_duration_seconds",
+		Help:      "Duracao do upload de midia por tipo e resultado.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"type", "result"})
+
+	// Eventos recebidos do WhatsApp
+	EventsReceived = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_received_total",
+		Help:      "Eventos recebidos do WhatsApp por tipo.",
+	}, []string{"type"})
+
+	// Webhooks
+	WebhookDeliveries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "webhook_deliveries_total",
+		Help:      "Entregas de webhook por resultado final (delivered, failed, aborted ou dropped).",
+	}, []string{"result"})
+	WebhookDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "webhook_request_duration_seconds",
+		Help:      "Duracao de cada tentativa de envio de webhook por resultado (success ou error).",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests, HTTPDuration, RateLimited,
+		MessagesSent, UploadBytes, UploadDuration,
+		EventsReceived,
+		WebhookDeliveries, WebhookDuration,
+	)
+}
+
+// Handler serve as metricas no formato do Prometheus. Com token, exige "Authorization: Bearer <token>" (as metricas trazem nomes de sessoes)
+func Handler(token string) http.Handler {
+	h := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+	if token == "" {
+		return h
+	}
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// Result rotulo de resultado a partir do erro
+func Result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
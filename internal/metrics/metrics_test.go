@@ -0,0 +1,35 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandlerToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		header string
+		want   int
+	}{
+		{name: "open", want: http.StatusOK},
+		{name: "valid bearer", token: "secret", header: "Bearer secret", want: http.StatusOK},
+		{name: "missing", token: "secret", want: http.StatusUnauthorized},
+		{name: "raw token", token: "secret", header: "secret", want: http.StatusUnauthorized},
+		{name: "wrong token", token: "secret", header: "Bearer other", want: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			w := httptest.NewRecorder()
+			Handler(tt.token).ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
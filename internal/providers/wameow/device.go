@@ -0,0 +1,41 @@
+package wameow
+
+import (
+	"context"
+	"fmt"
+)
+
+// deleteDevice desconecta o client e remove do container o device com chaves, sessoes Signal e app state
+func (m *Manager) deleteDevice(ctx context.Context, session *Session) error {
+	if session.Client != nil {
+		session.Client.RemoveEventHandlers()
+		session.Client.Disconnect()
+	}
+
+	if session.Device != nil && session.Device.ID != nil {
+		jid := session.Device.ID.String()
+		if err := session.Device.Delete(ctx); err != nil {
+			return fmt.Errorf("failed to delete device: %w", err)
+		}
+		m.log.Info().Str("name", session.Name).Str("jid", jid).Msg("Device removed from store")
+	}
+	return nil
+}
+
+// resetDevice apaga o device e deixa a sessao pronta para novo pareamento com o mesmo nome e token
+func (m *Manager) resetDevice(ctx context.Context, session *Session) error {
+	if err := m.deleteDevice(ctx, session); err != nil {
+		return err
+	}
+
+	session.Client = nil
+	session.Device = m.container.NewDevice()
+	session.clearPairing()
+	session.mu.Lock()
+	session.jid = ""
+	session.connected = false
+	session.mu.Unlock()
+
+	m.updateSessionInDB(session)
+	return nil
+}
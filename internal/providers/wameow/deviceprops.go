@@ -0,0 +1,91 @@
+package wameow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waCompanionReg"
+	"go.mau.fi/whatsmeow/proto/waWa6"
+	"go.mau.fi/whatsmeow/store"
+	"google.golang.org/protobuf/proto"
+)
+
+const maxDeviceNameLength = 64
+
+// devicePlatform plataforma exibida no celular e equivalente usado no pareamento por codigo
+type devicePlatform struct {
+	props       waCompanionReg.DeviceProps_PlatformType
+	pairClient  whatsmeow.PairClientType
+	pairDisplay string // formato "Browser (OS)" validado pelo servidor
+}
+
+var devicePlatforms = map[string]devicePlatform{
+	"chrome":  {waCompanionReg.DeviceProps_CHROME, whatsmeow.PairClientChrome, "Chrome (Linux)"},
+	"firefox": {waCompanionReg.DeviceProps_FIREFOX, whatsmeow.PairClientFirefox, "Firefox (Linux)"},
+	"safari":  {waCompanionReg.DeviceProps_SAFARI, whatsmeow.PairClientSafari, "Safari (Mac OS)"},
+	"edge":    {waCompanionReg.DeviceProps_EDGE, whatsmeow.PairClientEdge, "Edge (Windows)"},
+	"opera":   {waCompanionReg.DeviceProps_OPERA, whatsmeow.PairClientOpera, "Opera (Linux)"},
+	"desktop": {waCompanionReg.DeviceProps_DESKTOP, whatsmeow.PairClientChrome, "Chrome (Linux)"},
+}
+
+// DevicePlatforms lista as plataformas aceitas
+func DevicePlatforms() []string {
+	list := make([]string, 0, len(devicePlatforms))
+	for p := range devicePlatforms {
+		list = append(list, p)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func validateDeviceProps(name, platform string) error {
+	if len(name) > maxDeviceNameLength {
+		return fmt.Errorf("invalid device name: at most %d characters", maxDeviceNameLength)
+	}
+	if platform != "" {
+		if _, ok := devicePlatforms[platform]; !ok {
+			return fmt.Errorf("invalid device platform %q. Allowed: %s", platform, strings.Join(DevicePlatforms(), ", "))
+		}
+	}
+	return nil
+}
+
+// deviceProps retorna nome e plataforma da sessao, caindo no padrao global
+func (m *Manager) deviceProps(session *Session) (string, devicePlatform) {
+	name, platform := session.getDeviceProps()
+	if name == "" {
+		name = m.opts.DeviceName
+	}
+	if platform == "" {
+		platform = m.opts.DevicePlatform
+	}
+	p, ok := devicePlatforms[platform]
+	if !ok {
+		p = devicePlatforms["chrome"]
+	}
+	return name, p
+}
+
+// applyDeviceProps troca os DeviceProps do payload de registro (antes do pareamento) por sessao,
+// sem alterar store.DeviceProps global compartilhado pelas demais sessoes
+func (m *Manager) applyDeviceProps(client *whatsmeow.Client, session *Session) {
+	name, platform := m.deviceProps(session)
+	device := client.Store
+	client.GetClientPayload = func() *waWa6.ClientPayload {
+		payload := device.GetClientPayload()
+		if payload.DevicePairingData == nil {
+			return payload
+		}
+		props := proto.Clone(store.DeviceProps).(*waCompanionReg.DeviceProps)
+		if name != "" {
+			props.Os = proto.String(name)
+		}
+		props.PlatformType = platform.props.Enum()
+		if data, err := proto.Marshal(props); err == nil {
+			payload.DevicePairingData.DeviceProps = data
+		}
+		return payload
+	}
+}
@@ -0,0 +1,121 @@
+package wameow
+
+import (
+	"context"
+	"time"
+
+	"fiozap/internal/core"
+	"fiozap/internal/metrics"
+	"fiozap/internal/tracing"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Ready indica se o Manager carregou as sessoes e nao esta encerrando
+func (m *Manager) Ready() bool {
+	return m.loaded.Load() && !m.closing.Load()
+}
+
+// GetSessionHealth retorna o diagnostico de conectividade da sessao
+func (m *Manager) GetSessionHealth(ctx context.Context, name string) (*core.SessionHealth, error) {
+	session, err := m.getSessionInternal(name)
+	if err != nil {
+		return nil, err
+	}
+
+	health := &core.SessionHealth{
+		Name:   session.Name,
+		Status: session.GetStatus(),
+		Owned:  session.isOwned(),
+	}
+
+	session.mu.RLock()
+	health.LastEventAt = session.lastEventAt
+	health.LastKeepAliveTimeout = session.lastKeepAliveTimeout
+	health.LastSendError = session.lastSendError
+	health.LastSendErrorAt = session.lastSendErrorAt
+	session.mu.RUnlock()
+
+	if client := session.Client; client != nil {
+		health.WebsocketConnected = client.IsConnected()
+		health.LoggedIn = client.IsLoggedIn()
+		health.LastConnectedAt = client.LastSuccessfulConnect
+	}
+	if session.Device != nil && session.Device.ID != nil {
+		count, err := session.Device.PreKeys.UploadedPreKeyCount(ctx)
+		if err != nil {
+			m.log.Warn().Err(err).Str("name", name).Msg("Failed to count uploaded prekeys")
+		} else {
+			health.UploadedPreKeys = count
+		}
+	}
+
+	health.Healthy = health.WebsocketConnected && health.LoggedIn && health.Status == core.SessionConnected
+	return health, nil
+}
+
+// sendMessage envia pelo client registrando a metrica de envio e a ultima falha da sessao
+func (m *Manager) sendMessage(ctx context.Context, name string, client *whatsmeow.Client, to types.JID, msg *waE2E.Message, extra ...whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error) {
+	msgType := messageType(to, msg)
+	ctx, span := tracer.Start(ctx, "whatsmeow.SendMessage", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("fiozap.message.type", msgType)))
+	resp, err := client.SendMessage(ctx, to, msg, extra...)
+	tracing.End(span, err)
+	metrics.MessagesSent.WithLabelValues(msgType, metrics.Result(err)).Inc()
+	if session, lookupErr := m.getSessionInternal(name); lookupErr == nil {
+		session.recordSent(msgType, metrics.Result(err))
+		if err != nil {
+			session.recordSendError(err)
+		}
+	}
+	return resp, err
+}
+
+func (s *Session) recordEvent() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastEventAt = time.Now()
+}
+
+func (s *Session) recordKeepAliveTimeout() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastKeepAliveTimeout = time.Now()
+}
+
+// sentKey tipo e resultado de um envio
+type sentKey struct {
+	msgType string
+	result  string
+}
+
+func (s *Session) recordSent(msgType, result string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sent == nil {
+		s.sent = make(map[sentKey]uint64)
+	}
+	s.sent[sentKey{msgType, result}]++
+}
+
+// sentCounts copia os contadores de envio
+func (s *Session) sentCounts() map[sentKey]uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	counts := make(map[sentKey]uint64, len(s.sent))
+	for k, n := range s.sent {
+		counts[k] = n
+	}
+	return counts
+}
+
+func (s *Session) recordSendError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastSendError = err.Error()
+	s.lastSendErrorAt = time.Now()
+}
@@ -0,0 +1,139 @@
+package wameow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"fiozap/internal/core"
+
+	"go.mau.fi/whatsmeow"
+)
+
+// idleSweepInterval intervalo da verificacao de sessoes ociosas
+const idleSweepInterval = 30 * time.Second
+
+// minIdleTimeout menor politica de inatividade aceita
+const minIdleTimeout = time.Minute
+
+// validateIdleTimeout aceita zero (desativado) ou pelo menos minIdleTimeout
+func validateIdleTimeout(d time.Duration) error {
+	if d < 0 || (d > 0 && d < minIdleTimeout) {
+		return fmt.Errorf("invalid idle timeout: use 0 to disable or at least %d minute", int(minIdleTimeout/time.Minute))
+	}
+	return nil
+}
+
+// SetIdleTimeout altera a politica de inatividade da sessao. Vale a partir da proxima verificacao
+func (m *Manager) SetIdleTimeout(ctx context.Context, name string, timeout time.Duration) error {
+	session, err := m.getSessionInternal(name)
+	if err != nil {
+		return err
+	}
+	if err := validateIdleTimeout(timeout); err != nil {
+		return err
+	}
+
+	if err := m.repo.UpdateIdleTimeout(ctx, session.ID, int(timeout/time.Second)); err != nil {
+		return fmt.Errorf("failed to save idle timeout: %w", err)
+	}
+	session.setIdleTimeout(timeout)
+	session.touch()
+	m.log.Info().Str("name", name).Dur("idle_timeout", timeout).Msg("Session idle timeout updated")
+	return nil
+}
+
+// runIdle desconecta periodicamente as sessoes desta instancia sem uso da API
+func (m *Manager) runIdle() {
+	ticker := time.NewTicker(idleSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-m.ctx.Done():
+			return
+		}
+
+		for _, session := range m.ownedSessions() {
+			idle, timeout := session.idleSince()
+			if timeout <= 0 || idle < timeout || session.GetStatus() != core.SessionConnected {
+				continue
+			}
+			m.sleep(session)
+		}
+	}
+}
+
+// sleep desconecta a sessao ociosa mantendo o pareamento. A posse e liberada: a proxima
+// chamada da API reconecta em qualquer instancia
+func (m *Manager) sleep(session *Session) {
+	session.wakeMu.Lock()
+	defer session.wakeMu.Unlock()
+
+	// Pode ter sido usada enquanto aguardava o lock
+	if idle, timeout := session.idleSince(); timeout <= 0 || idle < timeout {
+		return
+	}
+
+	session.stopSupervisor()
+	if session.Client != nil {
+		session.Client.Disconnect()
+	}
+	session.setConnected(false)
+	m.updateSessionInDB(session)
+	m.setStatus(session, core.SessionIdle, "idle timeout")
+	m.release(session)
+	m.log.Info().Str("name", session.Name).Msg("Session disconnected after idle timeout")
+}
+
+// wake reconecta sob demanda uma sessao desligada por inatividade e aguarda events.Connected
+func (m *Manager) wake(session *Session) (*whatsmeow.Client, error) {
+	session.wakeMu.Lock()
+	defer session.wakeMu.Unlock()
+
+	// Outra chamada pode ter reconectado enquanto aguardava o lock
+	if session.Client != nil && session.Client.IsConnected() && session.IsConnected() {
+		return session.Client, nil
+	}
+	if session.GetStatus() != core.SessionIdle {
+		return nil, fmt.Errorf("session not connected")
+	}
+
+	events, unsubscribe := session.subscribeLogin()
+	defer unsubscribe()
+
+	ctx, cancel := context.WithTimeout(m.ctx, m.opts.IdleWakeTimeout)
+	defer cancel()
+
+	m.log.Info().Str("name", session.Name).Msg("Waking idle session")
+	if _, err := m.Connect(ctx, session.Name); err != nil {
+		return nil, fmt.Errorf("session not connected: wake failed: %w", err)
+	}
+
+	if err := waitForConnection(ctx, events); err != nil {
+		if ctx.Err() != nil {
+			// Segue conectando em background; a proxima chamada aproveita a conexao
+			return nil, fmt.Errorf("session not connected: timeout waiting for connection after %s", m.opts.IdleWakeTimeout)
+		}
+		return nil, err
+	}
+	return session.Client, nil
+}
+
+// waitForConnection aguarda events.Connected (via LoginEventConnected), erro de login ou o fim do ctx
+func waitForConnection(ctx context.Context, events <-chan core.LoginEvent) error {
+	for {
+		select {
+		case evt := <-events:
+			switch evt.Event {
+			case core.LoginEventConnected:
+				return nil
+			case core.LoginEventError:
+				return fmt.Errorf("session not connected: wake failed: %s", evt.Error)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
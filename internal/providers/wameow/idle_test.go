@@ -0,0 +1,178 @@
+package wameow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"fiozap/internal/core"
+	"fiozap/internal/integrations/webhook"
+	"fiozap/internal/repository"
+
+	"github.com/rs/zerolog"
+)
+
+// fakeSessionRepo registra as gravacoes feitas por sleep
+type fakeSessionRepo struct {
+	repository.SessionRepository
+	mu          sync.Mutex
+	connections []bool
+	statuses    []string
+}
+
+func (f *fakeSessionRepo) UpdateConnection(_ context.Context, _ string, connected bool, _, _, _ string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.connections = append(f.connections, connected)
+	return nil
+}
+
+func (f *fakeSessionRepo) UpdateStatus(_ context.Context, _, _, to, _ string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.statuses = append(f.statuses, to)
+	return nil
+}
+
+// fakeLeases registra as sessoes liberadas
+type fakeLeases struct {
+	repository.LeaseRepository
+	released []string
+}
+
+func (f *fakeLeases) Release(_ context.Context, sessionID, _ string) error {
+	f.released = append(f.released, sessionID)
+	return nil
+}
+
+func newIdleTestManager() (*Manager, *fakeSessionRepo, *fakeLeases) {
+	repo := &fakeSessionRepo{}
+	leases := &fakeLeases{}
+	m := &Manager{
+		sessions: make(map[string]*Session),
+		repo:     repo,
+		leases:   leases,
+		webhook:  webhook.NewDispatcher(zerolog.Nop()),
+		log:      zerolog.Nop(),
+		opts:     Options{InstanceID: "instance-a", IdleWakeTimeout: time.Second},
+	}
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	return m, repo, leases
+}
+
+func newIdleTestSession(idleFor time.Duration) *Session {
+	return &Session{
+		ID:           "id-vendas",
+		Name:         "vendas",
+		connected:    true,
+		status:       core.SessionConnected,
+		owned:        true,
+		idleTimeout:  time.Minute,
+		lastActivity: time.Now().Add(-idleFor),
+	}
+}
+
+func TestSleep(t *testing.T) {
+	tests := []struct {
+		name       string
+		idleFor    time.Duration
+		timeout    time.Duration
+		wantAsleep bool
+	}{
+		{name: "idle past timeout", idleFor: 2 * time.Minute, timeout: time.Minute, wantAsleep: true},
+		{name: "used recently", idleFor: 10 * time.Second, timeout: time.Minute},
+		{name: "policy disabled", idleFor: time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, repo, leases := newIdleTestManager()
+			session := newIdleTestSession(tt.idleFor)
+			session.idleTimeout = tt.timeout
+
+			m.sleep(session)
+
+			if !tt.wantAsleep {
+				if session.GetStatus() != core.SessionConnected || !session.isOwned() || len(leases.released) > 0 || len(repo.connections) > 0 {
+					t.Fatalf("session changed: status %s owned %v released %v", session.GetStatus(), session.isOwned(), leases.released)
+				}
+				return
+			}
+			if session.GetStatus() != core.SessionIdle || session.IsConnected() {
+				t.Fatalf("status = %s connected = %v, want idle and disconnected", session.GetStatus(), session.IsConnected())
+			}
+			if session.isOwned() || len(leases.released) != 1 || leases.released[0] != session.ID {
+				t.Fatalf("owned = %v released = %v, want lease released", session.isOwned(), leases.released)
+			}
+			if len(repo.connections) != 1 || repo.connections[0] || len(repo.statuses) != 1 || repo.statuses[0] != string(core.SessionIdle) {
+				t.Fatalf("persisted connections %v statuses %v", repo.connections, repo.statuses)
+			}
+		})
+	}
+}
+
+// Uso da API enquanto sleep aguarda wakeMu cancela a desconexao
+func TestSleepRechecksUnderWakeLock(t *testing.T) {
+	m, _, leases := newIdleTestManager()
+	session := newIdleTestSession(2 * time.Minute)
+
+	session.wakeMu.Lock()
+	done := make(chan struct{})
+	go func() {
+		m.sleep(session)
+		close(done)
+	}()
+	session.touch()
+	session.wakeMu.Unlock()
+	<-done
+
+	if session.GetStatus() != core.SessionConnected || !session.isOwned() || len(leases.released) > 0 {
+		t.Fatalf("session slept after being used: status %s released %v", session.GetStatus(), leases.released)
+	}
+}
+
+func TestWakeRequiresIdleSession(t *testing.T) {
+	m, _, _ := newIdleTestManager()
+	session := newIdleTestSession(0)
+	session.connected = false
+	session.status = core.SessionDisconnected
+
+	if _, err := m.wake(session); err == nil || !strings.Contains(err.Error(), "session not connected") {
+		t.Fatalf("wake error = %v, want session not connected", err)
+	}
+}
+
+func TestWaitForConnection(t *testing.T) {
+	tests := []struct {
+		name    string
+		events  []core.LoginEvent
+		wantErr error
+		errText string
+	}{
+		{name: "connected", events: []core.LoginEvent{{Event: core.LoginEventSuccess}, {Event: core.LoginEventConnected}}},
+		{name: "login error", events: []core.LoginEvent{{Event: core.LoginEventError, Error: "logged out"}}, errText: "wake failed: logged out"},
+		{name: "timeout", events: []core.LoginEvent{{Event: core.LoginEventSuccess}}, wantErr: context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := make(chan core.LoginEvent, len(tt.events))
+			for _, evt := range tt.events {
+				events <- evt
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			err := waitForConnection(ctx, events)
+			switch {
+			case tt.errText != "":
+				if err == nil || !strings.Contains(err.Error(), tt.errText) {
+					t.Fatalf("error = %v, want %q", err, tt.errText)
+				}
+			case !errors.Is(err, tt.wantErr):
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
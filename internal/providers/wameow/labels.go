@@ -0,0 +1,112 @@
+package wameow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"fiozap/internal/core"
+	"fiozap/internal/repository"
+)
+
+const (
+	maxSessionTags     = 50
+	maxTagLength       = 64
+	maxMetadataKeys    = 50
+	maxMetadataKeyLen  = 64
+	maxMetadataValLen  = 1024
+	maxSearchPageLimit = 500
+)
+
+// normalizeLabels valida metadados e tags; tags sao aparadas e deduplicadas mantendo a ordem
+func normalizeLabels(metadata map[string]string, tags []string) (map[string]string, []string, error) {
+	if len(metadata) > maxMetadataKeys {
+		return nil, nil, fmt.Errorf("invalid metadata: at most %d keys", maxMetadataKeys)
+	}
+	for k, v := range metadata {
+		if k == "" || len(k) > maxMetadataKeyLen {
+			return nil, nil, fmt.Errorf("invalid metadata key %q: must have 1-%d characters", k, maxMetadataKeyLen)
+		}
+		if len(v) > maxMetadataValLen {
+			return nil, nil, fmt.Errorf("invalid metadata value for %q: at most %d characters", k, maxMetadataValLen)
+		}
+	}
+
+	seen := make(map[string]struct{}, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t == "" || len(t) > maxTagLength {
+			return nil, nil, fmt.Errorf("invalid tag %q: must have 1-%d characters", t, maxTagLength)
+		}
+		if _, dup := seen[t]; dup {
+			continue
+		}
+		seen[t] = struct{}{}
+		normalized = append(normalized, t)
+	}
+	if len(normalized) > maxSessionTags {
+		return nil, nil, fmt.Errorf("invalid tags: at most %d tags", maxSessionTags)
+	}
+
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	return metadata, normalized, nil
+}
+
+// SetSessionLabels substitui metadados e tags da sessao
+func (m *Manager) SetSessionLabels(ctx context.Context, name string, metadata map[string]string, tags []string) error {
+	session, err := m.getSessionInternal(name)
+	if err != nil {
+		return err
+	}
+
+	metadata, tags, err = normalizeLabels(metadata, tags)
+	if err != nil {
+		return err
+	}
+
+	if err := m.repo.UpdateLabels(ctx, &repository.SessionModel{ID: session.ID, Metadata: metadata, Tags: tags}); err != nil {
+		return fmt.Errorf("failed to save labels: %w", err)
+	}
+	session.setLabels(metadata, tags)
+	return nil
+}
+
+// SearchSessions busca sessoes no banco (filtros, ordenacao e paginacao) e retorna as instancias em memoria
+func (m *Manager) SearchSessions(ctx context.Context, query core.SessionQuery) ([]core.Session, int, error) {
+	if query.Limit <= 0 || query.Limit > maxSearchPageLimit {
+		return nil, 0, fmt.Errorf("invalid limit: must be between 1 and %d", maxSearchPageLimit)
+	}
+	if query.Offset < 0 {
+		return nil, 0, fmt.Errorf("invalid offset: must not be negative")
+	}
+
+	models, total, err := m.repo.Search(ctx, repository.SessionFilter{
+		Status:     string(query.Status),
+		Tag:        query.Tag,
+		Phone:      query.Phone,
+		NamePrefix: query.NamePrefix,
+		TenantID:   query.TenantID,
+		Names:      query.Names,
+		SortBy:     query.SortBy,
+		Desc:       query.Desc,
+		Limit:      query.Limit,
+		Offset:     query.Offset,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search sessions: %w", err)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	list := make([]core.Session, 0, len(models))
+	for _, model := range models {
+		if s, ok := m.sessions[model.Name]; ok {
+			list = append(list, s)
+		}
+	}
+	return list, total, nil
+}
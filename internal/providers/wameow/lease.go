@@ -0,0 +1,324 @@
+package wameow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"fiozap/internal/core"
+	"fiozap/internal/repository"
+
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// errNotOwner sessao com posse valida de outra instancia
+var errNotOwner = errors.New("session owned by another instance")
+
+// orphanStatuses estados em que uma sessao sem dono deve ser assumida por outra instancia
+var orphanStatuses = []string{
+	string(core.SessionConnected),
+	string(core.SessionConnecting),
+	string(core.SessionReconnecting),
+}
+
+// claim toma a posse da sessao antes de conectar. Ao assumir de outra instancia,
+// recarrega a sessao do banco (pareamento, token e labels podem ter mudado la)
+func (m *Manager) claim(ctx context.Context, session *Session) error {
+	if session.isOwned() {
+		return nil
+	}
+
+	ok, err := m.leases.Acquire(ctx, session.ID, m.opts.InstanceID, m.opts.InstanceAddr, m.opts.LeaseTTL)
+	if err != nil {
+		return fmt.Errorf("failed to acquire session lease: %w", err)
+	}
+	if !ok {
+		return errNotOwner
+	}
+
+	model, err := m.repo.GetByName(ctx, session.Name)
+	if err == nil && model == nil {
+		err = fmt.Errorf("session %s not found", session.Name)
+	}
+	if err != nil {
+		_ = m.leases.Release(ctx, session.ID, m.opts.InstanceID)
+		return err
+	}
+	m.applyModel(session, model)
+
+	session.setOwned(true)
+	m.log.Info().Str("name", session.Name).Str("instance", m.opts.InstanceID).Msg("Session lease acquired")
+	return nil
+}
+
+// release devolve a posse (desconexao manual ou logout) para qualquer instancia poder conectar depois
+func (m *Manager) release(session *Session) {
+	if !session.isOwned() {
+		return
+	}
+	session.setOwned(false)
+	if err := m.leases.Release(context.Background(), session.ID, m.opts.InstanceID); err != nil {
+		m.log.Error().Err(err).Str("name", session.Name).Msg("Failed to release session lease")
+	}
+}
+
+// dropOwnership desliga localmente uma sessao cuja posse foi perdida, sem mexer no estado
+// persistido (o novo dono e quem o atualiza)
+func (m *Manager) dropOwnership(session *Session, reason string) {
+	session.setOwned(false)
+	session.stopSupervisor()
+	if session.Client != nil {
+		session.Client.RemoveEventHandlers()
+		session.Client.Disconnect()
+	}
+	session.setConnected(false)
+	m.log.Warn().Str("name", session.Name).Str("reason", reason).Msg("Session lease lost, disconnected locally")
+}
+
+// SessionOwner indica qual instancia atende a sessao. Sessoes sem dono valido sao locais
+func (m *Manager) SessionOwner(ctx context.Context, name string) (*core.SessionOwner, error) {
+	session, err := m.lookupSession(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	local := &core.SessionOwner{InstanceID: m.opts.InstanceID, Address: m.opts.InstanceAddr, Local: true}
+	if session.isOwned() {
+		return local, nil
+	}
+
+	lease, err := m.leases.Get(ctx, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session lease: %w", err)
+	}
+	if lease == nil || lease.Expired || lease.OwnerID == m.opts.InstanceID {
+		return local, nil
+	}
+	return &core.SessionOwner{InstanceID: lease.OwnerID, Address: lease.OwnerAddr.String}, nil
+}
+
+// lookupSession busca em memoria e, se nao achar, no banco (sessao criada em outra instancia)
+func (m *Manager) lookupSession(ctx context.Context, name string) (*Session, error) {
+	if session, err := m.getSessionInternal(name); err == nil {
+		return session, nil
+	}
+
+	model, err := m.repo.GetByName(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+	if model == nil {
+		return nil, fmt.Errorf("session %s not found", name)
+	}
+
+	// Monta fora do lock: carregar o device e uma consulta ao banco
+	session := m.newSessionFromModel(model)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, exists := m.sessions[name]; exists {
+		return existing, nil
+	}
+	m.sessions[name] = session
+	return session, nil
+}
+
+// runLeases renova as posses, espelha sessoes alteradas por outras instancias e assume orfas
+func (m *Manager) runLeases() {
+	ticker := time.NewTicker(m.opts.LeaseRenewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-m.ctx.Done():
+			return
+		}
+
+		m.renewLeases()
+		m.syncSessions()
+		m.claimOrphans()
+	}
+}
+
+func (m *Manager) renewLeases() {
+	ctx, cancel := context.WithTimeout(m.ctx, m.opts.LeaseRenewInterval)
+	defer cancel()
+
+	started := time.Now()
+	ids, err := m.leases.Renew(ctx, m.opts.InstanceID, m.opts.InstanceAddr, m.opts.LeaseTTL)
+	if err != nil {
+		m.log.Error().Err(err).Msg("Failed to renew session leases")
+		// Outra instancia pode assumir assim que o lease expira. Desliga antes disso: a proxima
+		// verificacao ja seria tarde demais
+		if time.Since(m.lastRenew) < m.leaseSafeWindow() {
+			return
+		}
+		ids = nil
+	} else {
+		// O banco calcula a validade a partir do inicio da chamada
+		m.lastRenew = started
+	}
+
+	kept := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		kept[id] = struct{}{}
+	}
+
+	for _, session := range m.ownedSessions() {
+		if _, ok := kept[session.ID]; !ok {
+			m.dropOwnership(session, "lease not renewed")
+		}
+	}
+}
+
+// leaseSafeWindow tempo sem renovar apos o qual a posse local e abandonada: um intervalo de
+// renovacao antes do TTL, para nunca haver duas instancias conectadas na mesma sessao
+func (m *Manager) leaseSafeWindow() time.Duration {
+	return m.opts.LeaseTTL - m.opts.LeaseRenewInterval
+}
+
+// syncSessions traz do banco sessoes criadas, alteradas ou removidas em outras instancias.
+// Consultas ao banco e desconexoes ficam fora de m.mu; sob o lock so o mapa e trocado
+func (m *Manager) syncSessions() {
+	models, err := m.repo.List(m.ctx)
+	if err != nil {
+		m.log.Error().Err(err).Msg("Failed to sync sessions from DB")
+		return
+	}
+	m.loaded.Store(true)
+
+	m.mu.RLock()
+	current := make(map[string]*Session, len(m.sessions))
+	for name, session := range m.sessions {
+		current[name] = session
+	}
+	m.mu.RUnlock()
+
+	// Sessoes novas ou recriadas (mesmo nome, outro id) sao montadas antes do lock
+	seen := make(map[string]struct{}, len(models))
+	created := make(map[string]*Session)
+	for _, model := range models {
+		seen[model.Name] = struct{}{}
+		session, exists := current[model.Name]
+		switch {
+		case !exists || session.ID != model.ID:
+			created[model.Name] = m.newSessionFromModel(model)
+		case !session.isOwned():
+			m.applyModel(session, model)
+		}
+	}
+
+	var replaced, removed []*Session
+	m.mu.Lock()
+	for name, session := range created {
+		// Mudou desde a leitura (ex.: criada ou importada localmente): prevalece o estado atual
+		if m.sessions[name] != current[name] {
+			continue
+		}
+		if old := current[name]; old != nil {
+			replaced = append(replaced, old)
+		}
+		m.sessions[name] = session
+	}
+	for name, session := range m.sessions {
+		if _, ok := seen[name]; !ok && !session.isOwned() && current[name] == session {
+			removed = append(removed, session)
+			delete(m.sessions, name)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, session := range replaced {
+		// Removida e recriada com o mesmo nome em outra instancia
+		if session.isOwned() {
+			m.dropOwnership(session, "session recreated")
+		} else {
+			session.stopSupervisor()
+		}
+	}
+	for _, session := range removed {
+		session.stopSupervisor()
+	}
+}
+
+// claimOrphans assume sessoes que deviam estar online mas cujo dono parou de renovar
+func (m *Manager) claimOrphans() {
+	names, err := m.leases.ListOrphaned(m.ctx, orphanStatuses)
+	if err != nil {
+		m.log.Error().Err(err).Msg("Failed to list orphaned sessions")
+		return
+	}
+
+	for _, name := range names {
+		session, err := m.getSessionInternal(name)
+		if err != nil || session.isOwned() {
+			continue
+		}
+		if err := m.claim(m.ctx, session); err != nil {
+			continue
+		}
+		m.log.Info().Str("name", name).Msg("Taking over orphaned session")
+		m.resume(session, "failover")
+	}
+}
+
+func (m *Manager) ownedSessions() []*Session {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var list []*Session
+	for _, s := range m.sessions {
+		if s.isOwned() {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// newSessionFromModel monta a sessao em memoria a partir do banco
+func (m *Manager) newSessionFromModel(model *repository.SessionModel) *Session {
+	session := &Session{ID: model.ID, Name: model.Name}
+	m.applyModel(session, model)
+	return session
+}
+
+// applyModel copia os dados persistidos para a sessao, recarregando o device se o pareamento mudou
+func (m *Manager) applyModel(session *Session, model *repository.SessionModel) {
+	jid := model.GetJID()
+	var device *store.Device
+	switch {
+	case jid == "":
+		if session.Device == nil || session.Device.ID != nil {
+			device = m.container.NewDevice()
+		}
+	case session.Device == nil || session.Device.ID == nil || session.Device.ID.String() != jid:
+		if parsedJID, err := types.ParseJID(jid); err == nil {
+			device, _ = m.container.GetDevice(context.Background(), parsedJID)
+		}
+		if device == nil && session.Device == nil {
+			device = m.container.NewDevice()
+		}
+	}
+	if device != nil {
+		session.Device = device
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.TenantID = model.TenantID.String
+	session.proxy = model.Proxy.String
+	session.metadata = model.Metadata
+	session.tags = model.Tags
+	session.deviceName = model.DeviceName.String
+	session.devicePlatform = model.DevicePlatform.String
+	session.idleTimeout = time.Duration(model.IdleTimeout) * time.Second
+	session.status = core.SessionStatus(model.Status)
+	session.jid = jid
+	session.tokenHash = model.TokenHash
+	session.tokenExpiresAt = model.TokenExpiresAt.Time
+	session.prevTokenHash = model.PreviousTokenHash.String
+	session.prevTokenExpiresAt = model.PreviousTokenExpiresAt.Time
+}
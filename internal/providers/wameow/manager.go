@@ -2,22 +2,22 @@ package wameow
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/hex"
 	"fmt"
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"fiozap/internal/core"
 	"fiozap/internal/integrations/webhook"
+	"fiozap/internal/metrics"
 	"fiozap/internal/repository"
 
 	"github.com/google/uuid"
 	"github.com/mdp/qrterminal/v3"
 	"github.com/rs/zerolog"
 	"go.mau.fi/whatsmeow"
-	"go.mau.fi/whatsmeow/store"
 	"go.mau.fi/whatsmeow/store/sqlstore"
 	"go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
@@ -26,23 +26,91 @@ import (
 // Manager gerencia sessoes WhatsApp usando whatsmeow
 type Manager struct {
 	sessions  map[string]*Session
+	importing map[string]string // nome -> JID de importacoes em andamento; protegido por mu
 	mu        sync.RWMutex
 	container *sqlstore.Container
 	repo      repository.SessionRepository
+	polls     repository.PollRepository
+	statuses  repository.StatusRepository
+	leases    repository.LeaseRepository
+	transfer  repository.TransferRepository
+	tenants   repository.TenantRepository
 	webhook   *webhook.Dispatcher
 	log       zerolog.Logger
+	opts      Options
+
+	reconnectSlots chan struct{}
+	reconnects     reconnectCounters
+
+	// Laços de fundo (posse de sessoes) e shutdown
+	ctx       context.Context
+	cancel    context.CancelFunc
+	lastRenew time.Time
+	closing   atomic.Bool
+	loaded    atomic.Bool // lista de sessoes carregada do banco
+}
+
+// Options parametros de comportamento do Manager
+type Options struct {
+	ReconnectConcurrency int           // reconexoes simultaneas (boot e supervisor)
+	ReconnectBaseDelay   time.Duration // espera da primeira retentativa
+	ReconnectMaxDelay    time.Duration // teto do backoff exponencial
+	DeviceName           string        // nome padrao em "Aparelhos conectados"
+	DevicePlatform       string        // plataforma padrao (ver DevicePlatforms)
+	InstanceID           string        // identificador unico da replica (dono das sessoes)
+	InstanceAddr         string        // URL interna da replica para encaminhar requisicoes (vazio = sem encaminhamento)
+	LeaseTTL             time.Duration // validade da posse sem renovacao
+	LeaseRenewInterval   time.Duration // intervalo de renovacao, sincronizacao e failover
+	IdleWakeTimeout      time.Duration // espera pela conexao ao reconectar uma sessao ociosa sob demanda
 }
 
 // New cria um novo Manager
-func New(container *sqlstore.Container, repo repository.SessionRepository, log zerolog.Logger, webhookDispatcher *webhook.Dispatcher) *Manager {
+func New(container *sqlstore.Container, repos *repository.Repositories, log zerolog.Logger, webhookDispatcher *webhook.Dispatcher, opts Options) *Manager {
+	if opts.ReconnectConcurrency <= 0 {
+		opts.ReconnectConcurrency = 5
+	}
+	if opts.ReconnectBaseDelay <= 0 {
+		opts.ReconnectBaseDelay = 2 * time.Second
+	}
+	if opts.ReconnectMaxDelay < opts.ReconnectBaseDelay {
+		opts.ReconnectMaxDelay = 5 * time.Minute
+	}
+	if _, ok := devicePlatforms[opts.DevicePlatform]; !ok {
+		opts.DevicePlatform = "chrome"
+	}
+	if opts.InstanceID == "" {
+		opts.InstanceID = uuid.New().String()
+	}
+	if opts.LeaseTTL <= 0 {
+		opts.LeaseTTL = 30 * time.Second
+	}
+	if opts.LeaseRenewInterval <= 0 || opts.LeaseRenewInterval >= opts.LeaseTTL {
+		opts.LeaseRenewInterval = opts.LeaseTTL / 3
+	}
+	if opts.IdleWakeTimeout <= 0 {
+		opts.IdleWakeTimeout = 20 * time.Second
+	}
+
 	m := &Manager{
-		sessions:  make(map[string]*Session),
-		container: container,
-		repo:      repo,
-		webhook:   webhookDispatcher,
-		log:       log.With().Str("component", "wameow").Logger(),
+		sessions:       make(map[string]*Session),
+		importing:      make(map[string]string),
+		container:      container,
+		repo:           repos.Session,
+		polls:          repos.Poll,
+		statuses:       repos.Status,
+		leases:         repos.Lease,
+		transfer:       repos.Transfer,
+		tenants:        repos.Tenant,
+		webhook:        webhookDispatcher,
+		log:            log.With().Str("component", "wameow").Logger(),
+		opts:           opts,
+		reconnectSlots: make(chan struct{}, opts.ReconnectConcurrency),
+		lastRenew:      time.Now(),
 	}
+	m.ctx, m.cancel = context.WithCancel(context.Background())
 	m.loadSessionsFromDB()
+	go m.runLeases()
+	go m.runIdle()
 	return m
 }
 
@@ -53,88 +121,130 @@ func (m *Manager) loadSessionsFromDB() {
 		m.log.Error().Err(err).Msg("Failed to load sessions from DB")
 		return
 	}
+	defer m.loaded.Store(true)
 
-	var sessionsToReconnect []string
-
-	for _, s := range sessions {
-		// Busca device do whatsmeow se existir JID
-		var device *store.Device
-		if s.JID.Valid && s.JID.String != "" {
-			parsedJID, _ := types.ParseJID(s.JID.String)
-			if !parsedJID.IsEmpty() {
-				device, _ = m.container.GetDevice(context.Background(), parsedJID)
-			}
-		}
-		if device == nil {
-			device = m.container.NewDevice()
+	// Sessoes com dono vivo em outra instancia ficam apenas espelhadas aqui
+	leased := make(map[string]bool)
+	if leases, err := m.leases.ListActive(context.Background()); err != nil {
+		m.log.Error().Err(err).Msg("Failed to load session leases")
+	} else {
+		for _, l := range leases {
+			leased[l.SessionID] = l.OwnerID != m.opts.InstanceID
 		}
+	}
 
-		session := &Session{
-			ID:     s.ID,
-			Name:   s.Name,
-			Token:  s.Token,
-			Device: device,
-		}
+	var sessionsToReconnect []*Session
+
+	for _, s := range sessions {
+		session := m.newSessionFromModel(s)
 		m.sessions[s.Name] = session
 		m.log.Info().Str("name", s.Name).Msg("Session loaded from DB")
 
-		// Marca para reconexão se estava conectada e tem JID (já pareada)
-		if s.Connected && s.JID.Valid && s.JID.String != "" {
-			sessionsToReconnect = append(sessionsToReconnect, s.Name)
+		if leased[s.ID] {
+			continue
 		}
-	}
 
-	// Reconecta sessões em background
-	for _, name := range sessionsToReconnect {
-		go m.reconnectSession(name)
-	}
-}
+		// Marca para reconexão se estava conectada (ou desligada pelo shutdown) e tem JID (já pareada)
+		if (s.Connected || s.ReconnectOnBoot) && s.JID.Valid && s.JID.String != "" {
+			sessionsToReconnect = append(sessionsToReconnect, session)
+			continue
+		}
 
-// reconnectSession tenta reconectar uma sessão
-func (m *Manager) reconnectSession(name string) {
-	m.log.Info().Str("name", name).Msg("Attempting to reconnect session")
-	_, err := m.Connect(context.Background(), name)
-	if err != nil {
-		m.log.Error().Err(err).Str("name", name).Msg("Failed to reconnect session")
+		// Estados transitorios nao sobrevivem ao reinicio
+		switch session.status {
+		case core.SessionPairing, core.SessionConnecting, core.SessionConnected, core.SessionReconnecting:
+			m.setStatus(session, idleStatus(session), "server restart")
+		}
 	}
-}
 
-func generateToken() string {
-	b := make([]byte, 32)
-	_, _ = rand.Read(b)
-	return hex.EncodeToString(b)
+	// Reconecta sessões em background, limitado por ReconnectConcurrency
+	for _, session := range sessionsToReconnect {
+		m.resume(session, "server restart")
+	}
 }
 
-// CreateSession cria uma nova sessao
-func (m *Manager) CreateSession(ctx context.Context, name string) (core.Session, error) {
+// CreateSession cria uma nova sessao. O token em texto puro so e retornado aqui
+func (m *Manager) CreateSession(ctx context.Context, name string, opts core.CreateSessionOptions) (core.Session, *core.SessionToken, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	if _, exists := m.sessions[name]; exists {
-		return nil, fmt.Errorf("session %s already exists", name)
+		return nil, nil, fmt.Errorf("session %s already exists", name)
+	}
+	if _, importing := m.importing[name]; importing {
+		return nil, nil, fmt.Errorf("session %s already exists", name)
+	}
+	if !opts.TokenExpiresAt.IsZero() && !opts.TokenExpiresAt.After(time.Now()) {
+		return nil, nil, fmt.Errorf("invalid token expiration: must be in the future")
+	}
+	if err := validateProxy(opts.Proxy); err != nil {
+		return nil, nil, err
+	}
+	metadata, tags, err := normalizeLabels(opts.Metadata, opts.Tags)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := validateDeviceProps(opts.DeviceName, opts.DevicePlatform); err != nil {
+		return nil, nil, err
+	}
+	if err := validateIdleTimeout(opts.IdleTimeout); err != nil {
+		return nil, nil, err
+	}
+	if opts.TenantID != "" && opts.MaxSessions > 0 && m.countTenantSessions(opts.TenantID) >= opts.MaxSessions {
+		return nil, nil, fmt.Errorf("session quota exceeded: tenant allows %d sessions", opts.MaxSessions)
 	}
 
+	token := generateToken()
 	session := &Session{
-		ID:     uuid.New().String(),
-		Name:   name,
-		Token:  generateToken(),
-		Device: m.container.NewDevice(),
+		ID:             uuid.New().String(),
+		Name:           name,
+		TenantID:       opts.TenantID,
+		proxy:          opts.Proxy,
+		metadata:       metadata,
+		tags:           tags,
+		deviceName:     opts.DeviceName,
+		devicePlatform: opts.DevicePlatform,
+		idleTimeout:    opts.IdleTimeout,
+		Device:         m.container.NewDevice(),
+		tokenHash:      hashToken(token),
+		tokenExpiresAt: opts.TokenExpiresAt,
 	}
 
 	// Persiste no banco
 	model := &repository.SessionModel{
-		ID:        session.ID,
-		Name:      session.Name,
-		Token:     session.Token,
-		Connected: false,
+		ID:             session.ID,
+		Name:           session.Name,
+		TenantID:       repository.NullString(opts.TenantID),
+		Proxy:          repository.NullString(opts.Proxy),
+		Metadata:       metadata,
+		Tags:           tags,
+		DeviceName:     repository.NullString(opts.DeviceName),
+		DevicePlatform: repository.NullString(opts.DevicePlatform),
+		IdleTimeout:    int(opts.IdleTimeout / time.Second),
+		TokenHash:      session.tokenHash,
+		TokenExpiresAt: repository.NullTime(opts.TokenExpiresAt),
+		Connected:      false,
+		Status:         string(core.SessionCreated),
 	}
 	if err := m.repo.Create(ctx, model); err != nil {
-		return nil, fmt.Errorf("failed to save session: %w", err)
+		return nil, nil, fmt.Errorf("failed to save session: %w", err)
 	}
+	m.setStatus(session, core.SessionCreated, "session created")
 
 	m.sessions[name] = session
 	m.log.Info().Str("name", name).Msg("Session created")
-	return session, nil
+	return session, &core.SessionToken{Token: token, ExpiresAt: opts.TokenExpiresAt}, nil
+}
+
+// countTenantSessions conta sessoes do tenant; chamar com m.mu travado
+func (m *Manager) countTenantSessions(tenantID string) int {
+	n := 0
+	for _, s := range m.sessions {
+		if s.TenantID == tenantID {
+			n++
+		}
+	}
+	return n
 }
 
 // GetSession retorna uma sessao existente
@@ -183,8 +293,9 @@ func (m *Manager) DeleteSession(ctx context.Context, name string) error {
 		return fmt.Errorf("session %s not found", name)
 	}
 
-	if session.Client != nil {
-		session.Client.Disconnect()
+	session.stopSupervisor()
+	if err := m.deleteDevice(ctx, session); err != nil {
+		return err
 	}
 
 	// Remove do banco
@@ -199,16 +310,40 @@ func (m *Manager) DeleteSession(ctx context.Context, name string) error {
 
 // Connect conecta uma sessao
 func (m *Manager) Connect(ctx context.Context, name string) (core.Session, error) {
+	ctx, span := startSpan(ctx, "Connect", name)
+	defer span.End()
+
 	session, err := m.getSessionInternal(name)
 	if err != nil {
 		return nil, err
 	}
 
+	if m.closing.Load() {
+		return nil, errShuttingDown
+	}
+	if err := m.claim(ctx, session); err != nil {
+		return nil, err
+	}
+	session.touch()
+
 	if session.Client != nil && session.Client.IsConnected() {
 		return session, nil
 	}
 
 	client := whatsmeow.NewClient(session.Device, nil)
+	if err := applyProxy(client, session.getProxy()); err != nil {
+		return nil, err
+	}
+	m.applyDeviceProps(client, session)
+	// Reconexao fica so com o supervisor; com o auto-reconnect do whatsmeow seriam dois donos
+	client.EnableAutoReconnect = false
+
+	// Um client anterior (ex.: tentativa que expirou) ainda reconectaria sozinho e entregaria
+	// eventos em duplicidade; precisa sair antes de ser substituido
+	if old := session.Client; old != nil {
+		old.RemoveEventHandlers()
+		old.Disconnect()
+	}
 	session.Client = client
 
 	client.AddEventHandler(func(evt interface{}) {
@@ -218,12 +353,17 @@ func (m *Manager) Connect(ctx context.Context, name string) (core.Session, error
 	if client.Store.ID == nil {
 		// Usa Background context para o QR channel não ser cancelado quando a requisição HTTP terminar
 		qrChan, _ := client.GetQRChannel(context.Background())
+		session.startPairing()
+		m.setStatus(session, core.SessionPairing, "")
 		if err := client.Connect(); err != nil {
+			m.setStatus(session, core.SessionFailed, err.Error())
 			return nil, fmt.Errorf("connect failed: %w", err)
 		}
 		go m.handleQR(session, qrChan)
 	} else {
+		m.setStatus(session, core.SessionConnecting, "")
 		if err := client.Connect(); err != nil {
+			m.setStatus(session, core.SessionFailed, err.Error())
 			return nil, fmt.Errorf("connect failed: %w", err)
 		}
 	}
@@ -238,16 +378,30 @@ func (m *Manager) handleQR(session *Session, qrChan <-chan whatsmeow.QRChannelIt
 		case "code":
 			qrCount++
 			session.setQRCode(evt.Code)
+			session.markQRReady()
+			session.publishLogin(core.LoginEvent{Event: core.LoginEventCode, Code: evt.Code, ExpiresAt: time.Now().Add(evt.Timeout)})
 			m.log.Info().Str("name", session.Name).Int("qr_number", qrCount).Msg("QR code received")
 			fmt.Printf("\n=== QR Code #%d for session '%s' (expires in ~20s) ===\n", qrCount, session.Name)
 			qrterminal.GenerateHalfBlock(evt.Code, qrterminal.L, os.Stdout)
 			fmt.Println("====================================================")
 		case "timeout":
-			session.setQRCode("")
+			session.clearPairing()
 			m.log.Warn().Str("name", session.Name).Int("qr_count", qrCount).Msg("QR code timeout - no more codes will be generated")
+			session.publishLogin(core.LoginEvent{Event: core.LoginEventTimeout})
+			m.setStatus(session, core.SessionCreated, "qr timeout")
+			m.webhook.Dispatch(context.Background(), session.Name, webhook.EventQRTimeout, nil)
 		case "success":
-			session.setQRCode("")
+			session.clearPairing()
 			m.log.Info().Str("name", session.Name).Msg("QR code scanned successfully")
+		default:
+			errMsg := evt.Event
+			if evt.Error != nil {
+				errMsg = evt.Error.Error()
+			}
+			session.clearPairing()
+			m.log.Warn().Str("name", session.Name).Str("event", evt.Event).Str("error", errMsg).Msg("Login failed")
+			session.publishLogin(core.LoginEvent{Event: core.LoginEventError, Error: errMsg})
+			m.setStatus(session, core.SessionFailed, errMsg)
 		}
 	}
 	m.log.Debug().Str("name", session.Name).Msg("QR channel closed")
@@ -255,13 +409,18 @@ func (m *Manager) handleQR(session *Session, qrChan <-chan whatsmeow.QRChannelIt
 
 func (m *Manager) handleEvent(session *Session, evt interface{}) {
 	ctx := context.Background()
+	session.recordEvent()
+	metrics.EventsReceived.WithLabelValues(eventType(evt)).Inc()
 
 	switch e := evt.(type) {
 	case *events.Connected:
 		session.setConnected(true)
+		session.touch()
 		session.setQRCode("")
 		m.updateSessionInDB(session)
+		m.setStatus(session, core.SessionConnected, "")
 		m.log.Info().Str("name", session.Name).Msg("Connected")
+		session.publishLogin(core.LoginEvent{Event: core.LoginEventConnected, JID: session.GetJID()})
 		m.webhook.Dispatch(ctx, session.Name, webhook.EventConnected, e)
 
 	case *events.PairSuccess:
@@ -271,24 +430,50 @@ func (m *Manager) handleEvent(session *Session, evt interface{}) {
 			session.mu.Unlock()
 		}
 		m.updateSessionInDB(session)
+		m.setStatus(session, core.SessionConnecting, "paired")
 		m.log.Info().Str("name", session.Name).Str("jid", e.ID.String()).Msg("Pair success")
+		session.publishLogin(core.LoginEvent{Event: core.LoginEventSuccess, JID: e.ID.String()})
 		m.webhook.Dispatch(ctx, session.Name, webhook.EventPairSuccess, e)
 
+	case *events.PairError:
+		m.setStatus(session, core.SessionFailed, e.Error.Error())
+		m.log.Warn().Err(e.Error).Str("name", session.Name).Msg("Pair failed")
+		session.publishLogin(core.LoginEvent{Event: core.LoginEventError, Error: e.Error.Error()})
+		m.webhook.Dispatch(ctx, session.Name, webhook.EventPairError, e)
+
 	case *events.Disconnected:
+		// Emitido apenas em quedas inesperadas (Disconnect() local nao gera o evento)
 		session.setConnected(false)
 		m.updateSessionInDB(session)
 		m.log.Info().Str("name", session.Name).Msg("Disconnected")
 		m.webhook.Dispatch(ctx, session.Name, webhook.EventDisconnected, e)
+		if session.Device != nil && session.Device.ID != nil {
+			m.setStatus(session, core.SessionReconnecting, "connection lost")
+			m.superviseReconnect(session, "connection lost", false)
+		} else {
+			m.setStatus(session, idleStatus(session), "connection lost")
+		}
 
 	case *events.LoggedOut:
 		session.setConnected(false)
-		m.updateSessionInDB(session)
+		m.setStatus(session, core.SessionLoggedOut, e.Reason.String())
+		session.publishLogin(core.LoginEvent{Event: core.LoginEventError, Error: "logged out: " + e.Reason.String()})
 		m.log.Warn().Str("name", session.Name).Msg("Logged out")
 		m.webhook.Dispatch(ctx, session.Name, webhook.EventLoggedOut, e)
+		// Fora do handler: remover event handlers de dentro de um deles trava o whatsmeow
+		go func() {
+			if err := m.resetDevice(context.Background(), session); err != nil {
+				m.log.Error().Err(err).Str("name", session.Name).Msg("Failed to reset device after logout")
+			}
+		}()
 
 	case *events.Message:
 		m.log.Debug().Str("name", session.Name).Str("from", e.Info.Sender.String()).Msg("Message received")
-		m.webhook.Dispatch(ctx, session.Name, webhook.EventMessage, e)
+		m.handlePollMessage(ctx, session, e)
+		if e.Info.Chat == types.StatusBroadcastJID {
+			m.handleStatusMessage(ctx, session, e)
+		}
+		m.webhook.Dispatch(ctx, session.Name, webhook.EventMessage, newMessagePayload(e))
 
 	case *events.Receipt:
 		m.log.Debug().Str("name", session.Name).Strs("ids", e.MessageIDs).Msg("Receipt received")
@@ -331,24 +516,42 @@ func (m *Manager) handleEvent(session *Session, evt interface{}) {
 		m.webhook.Dispatch(ctx, session.Name, webhook.EventCallTerminate, e)
 
 	case *events.KeepAliveTimeout:
+		session.recordKeepAliveTimeout()
 		m.log.Warn().Str("name", session.Name).Msg("Keep alive timeout")
 		m.webhook.Dispatch(ctx, session.Name, webhook.EventKeepAliveTimeout, e)
+		// Sem auto-reconnect o whatsmeow nao derruba a conexao travada; o supervisor assume
+		if time.Since(e.LastSuccess) > whatsmeow.KeepAliveMaxFailTime {
+			go m.forceReconnect(session, "keepalive failure")
+		}
 
 	case *events.KeepAliveRestored:
 		m.log.Info().Str("name", session.Name).Msg("Keep alive restored")
 		m.webhook.Dispatch(ctx, session.Name, webhook.EventKeepAliveRestored, e)
 
 	case *events.ConnectFailure:
+		m.setStatus(session, core.SessionFailed, e.Reason.String())
+		session.publishLogin(core.LoginEvent{Event: core.LoginEventError, Error: "connect failure: " + e.Reason.String()})
 		m.log.Error().Str("name", session.Name).Str("reason", e.Reason.String()).Msg("Connect failure")
 		m.webhook.Dispatch(ctx, session.Name, webhook.EventConnectFailure, e)
+		m.superviseReconnect(session, e.Reason.String(), false)
 
 	case *events.StreamError:
 		m.log.Error().Str("name", session.Name).Str("code", e.Code).Msg("Stream error")
 		m.webhook.Dispatch(ctx, session.Name, webhook.EventStreamError, e)
+		m.superviseReconnect(session, "stream error "+e.Code, false)
 
 	case *events.TemporaryBan:
+		m.setStatus(session, core.SessionBanned, e.String())
+		session.publishLogin(core.LoginEvent{Event: core.LoginEventError, Error: e.String()})
 		m.log.Warn().Str("name", session.Name).Str("reason", e.String()).Msg("Temporary ban")
 		m.webhook.Dispatch(ctx, session.Name, webhook.EventTemporaryBan, e)
+
+	case *events.StreamReplaced:
+		session.setConnected(false)
+		m.updateSessionInDB(session)
+		m.setStatus(session, core.SessionDisconnected, "stream replaced")
+		m.log.Warn().Str("name", session.Name).Msg("Stream replaced by another client")
+		m.webhook.Dispatch(ctx, session.Name, webhook.EventStreamReplaced, e)
 	}
 }
 
@@ -372,33 +575,54 @@ func (m *Manager) Disconnect(name string) error {
 		return err
 	}
 
+	session.stopSupervisor()
 	if session.Client != nil {
 		session.Client.Disconnect()
 		session.setConnected(false)
 	}
+	m.setStatus(session, idleStatus(session), "manual disconnect")
+	m.release(session)
 	return nil
 }
 
-// Logout faz logout da sessao (requer novo QR)
+// Logout faz logout da sessao e remove o device (requer novo QR, mantem nome e token)
 func (m *Manager) Logout(ctx context.Context, name string) error {
 	session, err := m.getSessionInternal(name)
 	if err != nil {
 		return err
 	}
 
+	session.stopSupervisor()
 	if session.Client != nil && session.Client.IsLoggedIn() {
-		return session.Client.Logout(ctx)
+		// Se o servidor recusar, o device e removido localmente mesmo assim
+		if err := session.Client.Logout(ctx); err != nil {
+			m.log.Warn().Err(err).Str("name", name).Msg("Logout request failed, removing device locally")
+		}
+	}
+
+	if err := m.resetDevice(ctx, session); err != nil {
+		return err
 	}
+	m.setStatus(session, core.SessionLoggedOut, "manual logout")
+	m.release(session)
 	return nil
 }
 
 // getClient retorna client conectado ou erro
 func (m *Manager) getClient(name string) (*whatsmeow.Client, error) {
+	if m.closing.Load() {
+		return nil, errShuttingDown
+	}
 	session, err := m.getSessionInternal(name)
 	if err != nil {
 		return nil, err
 	}
+	session.touch()
 	if session.Client == nil || !session.Client.IsConnected() {
+		// Desligada por inatividade: reconecta sob demanda
+		if session.GetStatus() == core.SessionIdle {
+			return m.wake(session)
+		}
 		return nil, fmt.Errorf("session not connected")
 	}
 	return session.Client, nil
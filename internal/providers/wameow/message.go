@@ -3,22 +3,28 @@ package wameow
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"fiozap/internal/core"
 
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/appstate"
 	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
 	"google.golang.org/protobuf/proto"
 )
 
 // SendText envia mensagem de texto
 func (m *Manager) SendText(ctx context.Context, session, to, text string) (*core.MessageResponse, error) {
+	ctx, span := startSpan(ctx, "SendText", session)
+	defer span.End()
+
 	client, err := m.getClient(session)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := client.SendMessage(ctx, parseJID(to), &waE2E.Message{
+	resp, err := m.sendMessage(ctx, session, client, parseJID(to), &waE2E.Message{
 		Conversation: proto.String(text),
 	})
 	if err != nil {
@@ -28,30 +34,28 @@ func (m *Manager) SendText(ctx context.Context, session, to, text string) (*core
 	return &core.MessageResponse{ID: resp.ID, Timestamp: resp.Timestamp}, nil
 }
 
-// SendImage envia imagem
-func (m *Manager) SendImage(ctx context.Context, session, to string, data []byte, caption, mimeType string) (*core.MessageResponse, error) {
+// SendImage envia imagem (opcionalmente visualizacao unica)
+func (m *Manager) SendImage(ctx context.Context, session, to string, data []byte, caption, mimeType string, viewOnce bool) (*core.MessageResponse, error) {
+	ctx, span := startSpan(ctx, "SendImage", session)
+	defer span.End()
+
 	client, err := m.getClient(session)
 	if err != nil {
 		return nil, err
 	}
 
-	uploaded, err := client.Upload(ctx, data, whatsmeow.MediaImage)
+	uploaded, err := m.upload(ctx, client, data, whatsmeow.MediaImage)
 	if err != nil {
 		return nil, fmt.Errorf("upload failed: %w", err)
 	}
 
-	resp, err := client.SendMessage(ctx, parseJID(to), &waE2E.Message{
-		ImageMessage: &waE2E.ImageMessage{
-			URL:           proto.String(uploaded.URL),
-			DirectPath:    proto.String(uploaded.DirectPath),
-			MediaKey:      uploaded.MediaKey,
-			Mimetype:      proto.String(mimeType),
-			FileEncSHA256: uploaded.FileEncSHA256,
-			FileSHA256:    uploaded.FileSHA256,
-			FileLength:    proto.Uint64(uint64(len(data))),
-			Caption:       proto.String(caption),
-		},
-	})
+	msg := imageMessage(uploaded, len(data), caption, mimeType)
+	if viewOnce {
+		msg.ImageMessage.ViewOnce = proto.Bool(true)
+		msg = wrapViewOnce(msg)
+	}
+
+	resp, err := m.sendMessage(ctx, session, client, parseJID(to), msg)
 	if err != nil {
 		return nil, fmt.Errorf("send failed: %w", err)
 	}
@@ -59,50 +63,81 @@ func (m *Manager) SendImage(ctx context.Context, session, to string, data []byte
 	return &core.MessageResponse{ID: resp.ID, Timestamp: resp.Timestamp}, nil
 }
 
-// SendVideo envia video
-func (m *Manager) SendVideo(ctx context.Context, session, to string, data []byte, caption, mimeType string) (*core.MessageResponse, error) {
+// SendVideo envia video (opcionalmente visualizacao unica)
+func (m *Manager) SendVideo(ctx context.Context, session, to string, data []byte, caption, mimeType string, viewOnce bool) (*core.MessageResponse, error) {
+	ctx, span := startSpan(ctx, "SendVideo", session)
+	defer span.End()
+
 	client, err := m.getClient(session)
 	if err != nil {
 		return nil, err
 	}
 
-	uploaded, err := client.Upload(ctx, data, whatsmeow.MediaVideo)
+	uploaded, err := m.upload(ctx, client, data, whatsmeow.MediaVideo)
 	if err != nil {
 		return nil, fmt.Errorf("upload failed: %w", err)
 	}
 
-	resp, err := client.SendMessage(ctx, parseJID(to), &waE2E.Message{
-		VideoMessage: &waE2E.VideoMessage{
+	msg := videoMessage(uploaded, len(data), caption, mimeType)
+	if viewOnce {
+		msg.VideoMessage.ViewOnce = proto.Bool(true)
+		msg = wrapViewOnce(msg)
+	}
+
+	resp, err := m.sendMessage(ctx, session, client, parseJID(to), msg)
+	if err != nil {
+		return nil, fmt.Errorf("send failed: %w", err)
+	}
+
+	return &core.MessageResponse{ID: resp.ID, Timestamp: resp.Timestamp}, nil
+}
+
+func imageMessage(uploaded whatsmeow.UploadResponse, size int, caption, mimeType string) *waE2E.Message {
+	return &waE2E.Message{
+		ImageMessage: &waE2E.ImageMessage{
 			URL:           proto.String(uploaded.URL),
 			DirectPath:    proto.String(uploaded.DirectPath),
 			MediaKey:      uploaded.MediaKey,
 			Mimetype:      proto.String(mimeType),
 			FileEncSHA256: uploaded.FileEncSHA256,
 			FileSHA256:    uploaded.FileSHA256,
-			FileLength:    proto.Uint64(uint64(len(data))),
+			FileLength:    proto.Uint64(uint64(size)),
 			Caption:       proto.String(caption),
 		},
-	})
-	if err != nil {
-		return nil, fmt.Errorf("send failed: %w", err)
 	}
+}
 
-	return &core.MessageResponse{ID: resp.ID, Timestamp: resp.Timestamp}, nil
+func videoMessage(uploaded whatsmeow.UploadResponse, size int, caption, mimeType string) *waE2E.Message {
+	return &waE2E.Message{
+		VideoMessage: &waE2E.VideoMessage{
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			MediaKey:      uploaded.MediaKey,
+			Mimetype:      proto.String(mimeType),
+			FileEncSHA256: uploaded.FileEncSHA256,
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    proto.Uint64(uint64(size)),
+			Caption:       proto.String(caption),
+		},
+	}
 }
 
-// SendAudio envia audio
-func (m *Manager) SendAudio(ctx context.Context, session, to string, data []byte, mimeType string) (*core.MessageResponse, error) {
+// SendAudio envia audio (opcionalmente visualizacao unica)
+func (m *Manager) SendAudio(ctx context.Context, session, to string, data []byte, mimeType string, viewOnce bool) (*core.MessageResponse, error) {
+	ctx, span := startSpan(ctx, "SendAudio", session)
+	defer span.End()
+
 	client, err := m.getClient(session)
 	if err != nil {
 		return nil, err
 	}
 
-	uploaded, err := client.Upload(ctx, data, whatsmeow.MediaAudio)
+	uploaded, err := m.upload(ctx, client, data, whatsmeow.MediaAudio)
 	if err != nil {
 		return nil, fmt.Errorf("upload failed: %w", err)
 	}
 
-	resp, err := client.SendMessage(ctx, parseJID(to), &waE2E.Message{
+	msg := &waE2E.Message{
 		AudioMessage: &waE2E.AudioMessage{
 			URL:           proto.String(uploaded.URL),
 			DirectPath:    proto.String(uploaded.DirectPath),
@@ -112,7 +147,13 @@ func (m *Manager) SendAudio(ctx context.Context, session, to string, data []byte
 			FileSHA256:    uploaded.FileSHA256,
 			FileLength:    proto.Uint64(uint64(len(data))),
 		},
-	})
+	}
+	if viewOnce {
+		msg.AudioMessage.ViewOnce = proto.Bool(true)
+		msg = wrapViewOnce(msg)
+	}
+
+	resp, err := m.sendMessage(ctx, session, client, parseJID(to), msg)
 	if err != nil {
 		return nil, fmt.Errorf("send failed: %w", err)
 	}
@@ -122,17 +163,20 @@ func (m *Manager) SendAudio(ctx context.Context, session, to string, data []byte
 
 // SendDocument envia documento
 func (m *Manager) SendDocument(ctx context.Context, session, to string, data []byte, filename, mimeType string) (*core.MessageResponse, error) {
+	ctx, span := startSpan(ctx, "SendDocument", session)
+	defer span.End()
+
 	client, err := m.getClient(session)
 	if err != nil {
 		return nil, err
 	}
 
-	uploaded, err := client.Upload(ctx, data, whatsmeow.MediaDocument)
+	uploaded, err := m.upload(ctx, client, data, whatsmeow.MediaDocument)
 	if err != nil {
 		return nil, fmt.Errorf("upload failed: %w", err)
 	}
 
-	resp, err := client.SendMessage(ctx, parseJID(to), &waE2E.Message{
+	resp, err := m.sendMessage(ctx, session, client, parseJID(to), &waE2E.Message{
 		DocumentMessage: &waE2E.DocumentMessage{
 			URL:           proto.String(uploaded.URL),
 			DirectPath:    proto.String(uploaded.DirectPath),
@@ -153,17 +197,20 @@ func (m *Manager) SendDocument(ctx context.Context, session, to string, data []b
 
 // SendSticker envia sticker
 func (m *Manager) SendSticker(ctx context.Context, session, to string, data []byte, mimeType string) (*core.MessageResponse, error) {
+	ctx, span := startSpan(ctx, "SendSticker", session)
+	defer span.End()
+
 	client, err := m.getClient(session)
 	if err != nil {
 		return nil, err
 	}
 
-	uploaded, err := client.Upload(ctx, data, whatsmeow.MediaImage)
+	uploaded, err := m.upload(ctx, client, data, whatsmeow.MediaImage)
 	if err != nil {
 		return nil, fmt.Errorf("upload failed: %w", err)
 	}
 
-	resp, err := client.SendMessage(ctx, parseJID(to), &waE2E.Message{
+	resp, err := m.sendMessage(ctx, session, client, parseJID(to), &waE2E.Message{
 		StickerMessage: &waE2E.StickerMessage{
 			URL:           proto.String(uploaded.URL),
 			DirectPath:    proto.String(uploaded.DirectPath),
@@ -183,12 +230,15 @@ func (m *Manager) SendSticker(ctx context.Context, session, to string, data []by
 
 // SendLocation envia localizacao
 func (m *Manager) SendLocation(ctx context.Context, session, to string, lat, lng float64, name, address string) (*core.MessageResponse, error) {
+	ctx, span := startSpan(ctx, "SendLocation", session)
+	defer span.End()
+
 	client, err := m.getClient(session)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := client.SendMessage(ctx, parseJID(to), &waE2E.Message{
+	resp, err := m.sendMessage(ctx, session, client, parseJID(to), &waE2E.Message{
 		LocationMessage: &waE2E.LocationMessage{
 			DegreesLatitude:  proto.Float64(lat),
 			DegreesLongitude: proto.Float64(lng),
@@ -205,12 +255,15 @@ func (m *Manager) SendLocation(ctx context.Context, session, to string, lat, lng
 
 // SendContact envia contato
 func (m *Manager) SendContact(ctx context.Context, session, to, name, vcard string) (*core.MessageResponse, error) {
+	ctx, span := startSpan(ctx, "SendContact", session)
+	defer span.End()
+
 	client, err := m.getClient(session)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := client.SendMessage(ctx, parseJID(to), &waE2E.Message{
+	resp, err := m.sendMessage(ctx, session, client, parseJID(to), &waE2E.Message{
 		ContactMessage: &waE2E.ContactMessage{
 			DisplayName: proto.String(name),
 			Vcard:       proto.String(vcard),
@@ -225,6 +278,9 @@ func (m *Manager) SendContact(ctx context.Context, session, to, name, vcard stri
 
 // SendPoll envia enquete
 func (m *Manager) SendPoll(ctx context.Context, session, to, question string, options []string, multiSelect bool) (*core.MessageResponse, error) {
+	ctx, span := startSpan(ctx, "SendPoll", session)
+	defer span.End()
+
 	client, err := m.getClient(session)
 	if err != nil {
 		return nil, err
@@ -235,17 +291,26 @@ func (m *Manager) SendPoll(ctx context.Context, session, to, question string, op
 		selectCount = len(options)
 	}
 
+	jid := parseJID(to)
 	msg := client.BuildPollCreation(question, options, selectCount)
-	resp, err := client.SendMessage(ctx, parseJID(to), msg)
+	resp, err := m.sendMessage(ctx, session, client, jid, msg)
 	if err != nil {
 		return nil, fmt.Errorf("send failed: %w", err)
 	}
 
+	// Guarda as opcoes para mapear os hashes dos votos recebidos
+	if s, err := m.getSessionInternal(session); err == nil {
+		m.storePoll(ctx, s, jid, client.Store.ID.ToNonAD(), resp.ID, msg.GetPollCreationMessage())
+	}
+
 	return &core.MessageResponse{ID: resp.ID, Timestamp: resp.Timestamp}, nil
 }
 
 // SendReaction envia reacao
 func (m *Manager) SendReaction(ctx context.Context, session, to, messageID, emoji string) (*core.MessageResponse, error) {
+	ctx, span := startSpan(ctx, "SendReaction", session)
+	defer span.End()
+
 	client, err := m.getClient(session)
 	if err != nil {
 		return nil, err
@@ -253,7 +318,7 @@ func (m *Manager) SendReaction(ctx context.Context, session, to, messageID, emoj
 
 	jid := parseJID(to)
 	msg := client.BuildReaction(jid, client.Store.ID.ToNonAD(), messageID, emoji)
-	resp, err := client.SendMessage(ctx, jid, msg)
+	resp, err := m.sendMessage(ctx, session, client, jid, msg)
 	if err != nil {
 		return nil, fmt.Errorf("send failed: %w", err)
 	}
@@ -261,16 +326,33 @@ func (m *Manager) SendReaction(ctx context.Context, session, to, messageID, emoj
 	return &core.MessageResponse{ID: resp.ID, Timestamp: resp.Timestamp}, nil
 }
 
-// EditMessage edita mensagem
-func (m *Manager) EditMessage(ctx context.Context, session, chat, messageID, newText string) (*core.MessageResponse, error) {
+// EditMessage edita texto ou legenda de midia (image, video, document)
+func (m *Manager) EditMessage(ctx context.Context, session, chat, messageID, newText, mediaType string) (*core.MessageResponse, error) {
+	ctx, span := startSpan(ctx, "EditMessage", session)
+	defer span.End()
+
 	client, err := m.getClient(session)
 	if err != nil {
 		return nil, err
 	}
 
+	var content *waE2E.Message
+	switch mediaType {
+	case "", "text":
+		content = &waE2E.Message{Conversation: proto.String(newText)}
+	case "image":
+		content = &waE2E.Message{ImageMessage: &waE2E.ImageMessage{Caption: proto.String(newText)}}
+	case "video":
+		content = &waE2E.Message{VideoMessage: &waE2E.VideoMessage{Caption: proto.String(newText)}}
+	case "document":
+		content = &waE2E.Message{DocumentMessage: &waE2E.DocumentMessage{Caption: proto.String(newText)}}
+	default:
+		return nil, fmt.Errorf("unsupported edit type: %s", mediaType)
+	}
+
 	jid := parseJID(chat)
-	msg := client.BuildEdit(jid, messageID, &waE2E.Message{Conversation: proto.String(newText)})
-	resp, err := client.SendMessage(ctx, jid, msg)
+	msg := client.BuildEdit(jid, messageID, content)
+	resp, err := m.sendMessage(ctx, session, client, jid, msg)
 	if err != nil {
 		return nil, fmt.Errorf("edit failed: %w", err)
 	}
@@ -278,19 +360,128 @@ func (m *Manager) EditMessage(ctx context.Context, session, chat, messageID, new
 	return &core.MessageResponse{ID: resp.ID, Timestamp: resp.Timestamp}, nil
 }
 
-// RevokeMessage revoga/apaga mensagem
-func (m *Manager) RevokeMessage(ctx context.Context, session, chat, messageID string) (*core.MessageResponse, error) {
+// RevokeMessage revoga/apaga mensagem. Com sender de outro participante, apaga como admin do grupo
+func (m *Manager) RevokeMessage(ctx context.Context, session, chat, sender, messageID string) (*core.MessageResponse, error) {
+	ctx, span := startSpan(ctx, "RevokeMessage", session)
+	defer span.End()
+
 	client, err := m.getClient(session)
 	if err != nil {
 		return nil, err
 	}
 
 	jid := parseJID(chat)
-	msg := client.BuildRevoke(jid, client.Store.ID.ToNonAD(), messageID)
-	resp, err := client.SendMessage(ctx, jid, msg)
+	msg := client.BuildRevoke(jid, parseSenderJID(sender), messageID)
+	resp, err := m.sendMessage(ctx, session, client, jid, msg)
 	if err != nil {
 		return nil, fmt.Errorf("revoke failed: %w", err)
 	}
 
 	return &core.MessageResponse{ID: resp.ID, Timestamp: resp.Timestamp}, nil
 }
+
+// PinMessage fixa ou desafixa mensagem no chat
+func (m *Manager) PinMessage(ctx context.Context, session, chat, sender, messageID string, pin bool, duration time.Duration) (*core.MessageResponse, error) {
+	ctx, span := startSpan(ctx, "PinMessage", session)
+	defer span.End()
+
+	client, err := m.getClient(session)
+	if err != nil {
+		return nil, err
+	}
+
+	jid := parseJID(chat)
+	pinType := waE2E.PinInChatMessage_UNPIN_FOR_ALL
+	if pin {
+		pinType = waE2E.PinInChatMessage_PIN_FOR_ALL
+	}
+
+	msg := &waE2E.Message{
+		PinInChatMessage: &waE2E.PinInChatMessage{
+			Key:               client.BuildMessageKey(jid, parseSenderJID(sender), messageID),
+			Type:              pinType.Enum(),
+			SenderTimestampMS: proto.Int64(time.Now().UnixMilli()),
+		},
+	}
+	if pin {
+		msg.MessageContextInfo = &waE2E.MessageContextInfo{
+			MessageAddOnDurationInSecs: proto.Uint32(uint32(duration.Seconds())),
+		}
+	}
+
+	resp, err := m.sendMessage(ctx, session, client, jid, msg)
+	if err != nil {
+		return nil, fmt.Errorf("pin failed: %w", err)
+	}
+
+	return &core.MessageResponse{ID: resp.ID, Timestamp: resp.Timestamp}, nil
+}
+
+// StarMessage marca ou desmarca mensagem com estrela
+func (m *Manager) StarMessage(ctx context.Context, session, chat, sender, messageID string, star bool) error {
+	ctx, span := startSpan(ctx, "StarMessage", session)
+	defer span.End()
+
+	client, err := m.getClient(session)
+	if err != nil {
+		return err
+	}
+
+	jid := parseJID(chat)
+	senderJID := parseSenderJID(sender)
+	fromMe := client.BuildMessageKey(jid, senderJID, messageID).GetFromMe()
+	if fromMe {
+		// Mensagens proprias usam participante "0" no indice do app state
+		senderJID = jid
+	}
+
+	if err := client.SendAppState(ctx, appstate.BuildStar(jid, senderJID, messageID, fromMe, star)); err != nil {
+		return fmt.Errorf("star failed: %w", err)
+	}
+	return nil
+}
+
+// KeepMessage mantem ou libera mensagem temporaria no chat
+func (m *Manager) KeepMessage(ctx context.Context, session, chat, sender, messageID string, keep bool) (*core.MessageResponse, error) {
+	ctx, span := startSpan(ctx, "KeepMessage", session)
+	defer span.End()
+
+	client, err := m.getClient(session)
+	if err != nil {
+		return nil, err
+	}
+
+	jid := parseJID(chat)
+	keepType := waE2E.KeepType_UNDO_KEEP_FOR_ALL
+	if keep {
+		keepType = waE2E.KeepType_KEEP_FOR_ALL
+	}
+
+	resp, err := m.sendMessage(ctx, session, client, jid, &waE2E.Message{
+		KeepInChatMessage: &waE2E.KeepInChatMessage{
+			Key:         client.BuildMessageKey(jid, parseSenderJID(sender), messageID),
+			KeepType:    keepType.Enum(),
+			TimestampMS: proto.Int64(time.Now().UnixMilli()),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("keep failed: %w", err)
+	}
+
+	return &core.MessageResponse{ID: resp.ID, Timestamp: resp.Timestamp}, nil
+}
+
+// wrapViewOnce encapsula a midia no container de visualizacao unica
+func wrapViewOnce(msg *waE2E.Message) *waE2E.Message {
+	return &waE2E.Message{
+		ViewOnceMessageV2: &waE2E.FutureProofMessage{Message: msg},
+	}
+}
+
+// parseSenderJID converte o autor da mensagem original (vazio = propria sessao)
+func parseSenderJID(sender string) types.JID {
+	if sender == "" {
+		return types.EmptyJID
+	}
+	return parseJID(sender)
+}
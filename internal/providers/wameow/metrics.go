@@ -0,0 +1,170 @@
+package wameow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"fiozap/internal/metrics"
+	"fiozap/internal/tracing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// upload envia a midia ao WhatsApp registrando tamanho e duracao
+func (m *Manager) upload(ctx context.Context, client *whatsmeow.Client, data []byte, mediaType whatsmeow.MediaType) (whatsmeow.UploadResponse, error) {
+	label := mediaLabel(mediaType)
+	ctx, span := tracer.Start(ctx, "whatsmeow.Upload", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("fiozap.media.type", label), attribute.Int("fiozap.media.bytes", len(data))))
+	start := time.Now()
+	uploaded, err := client.Upload(ctx, data, mediaType)
+	tracing.End(span, err)
+	metrics.UploadDuration.WithLabelValues(label, metrics.Result(err)).Observe(time.Since(start).Seconds())
+	if err == nil {
+		metrics.UploadBytes.WithLabelValues(label).Observe(float64(len(data)))
+	}
+	return uploaded, err
+}
+
+func mediaLabel(mediaType whatsmeow.MediaType) string {
+	switch mediaType {
+	case whatsmeow.MediaImage:
+		return "image"
+	case whatsmeow.MediaVideo:
+		return "video"
+	case whatsmeow.MediaAudio:
+		return "audio"
+	case whatsmeow.MediaDocument:
+		return "document"
+	default:
+		return "other"
+	}
+}
+
+// messageType rotulo do tipo da mensagem enviada
+func messageType(to types.JID, msg *waE2E.Message) string {
+	if to == types.StatusBroadcastJID {
+		return "status"
+	}
+	switch {
+	case msg.GetViewOnceMessage() != nil:
+		return messageType(to, msg.GetViewOnceMessage().GetMessage())
+	case msg.GetViewOnceMessageV2() != nil:
+		return messageType(to, msg.GetViewOnceMessageV2().GetMessage())
+	case msg.Conversation != nil, msg.ExtendedTextMessage != nil:
+		return "text"
+	case msg.ImageMessage != nil:
+		return "image"
+	case msg.VideoMessage != nil:
+		return "video"
+	case msg.AudioMessage != nil:
+		return "audio"
+	case msg.DocumentMessage != nil:
+		return "document"
+	case msg.StickerMessage != nil:
+		return "sticker"
+	case msg.LocationMessage != nil:
+		return "location"
+	case msg.ContactMessage != nil:
+		return "contact"
+	case msg.PollCreationMessage != nil, msg.PollCreationMessageV2 != nil, msg.PollCreationMessageV3 != nil:
+		return "poll"
+	case msg.ReactionMessage != nil:
+		return "reaction"
+	case msg.ProtocolMessage != nil:
+		return "protocol" // edicao e revogacao
+	case msg.PinInChatMessage != nil:
+		return "pin"
+	case msg.KeepInChatMessage != nil:
+		return "keep"
+	default:
+		return "other"
+	}
+}
+
+// eventType rotulo do evento do whatsmeow (ex.: *events.Message vira Message)
+func eventType(evt interface{}) string {
+	name := fmt.Sprintf("%T", evt)
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+var (
+	sessionsDesc = prometheus.NewDesc(
+		"fiozap_sessions",
+		"Sessoes conhecidas pela instancia por estado.",
+		[]string{"state"}, nil,
+	)
+	reconnectAttemptsDesc = prometheus.NewDesc(
+		"fiozap_reconnect_attempts_total",
+		"Tentativas de reconexao do supervisor.",
+		nil, nil,
+	)
+	reconnectResultsDesc = prometheus.NewDesc(
+		"fiozap_reconnect_results_total",
+		"Resultados das reconexoes do supervisor (success ou failure).",
+		[]string{"result"}, nil,
+	)
+	sessionMessagesSentDesc = prometheus.NewDesc(
+		"fiozap_session_messages_sent_total",
+		"Mensagens enviadas por sessao, tipo e resultado (success ou error). Sessoes removidas saem da serie.",
+		[]string{"session", "type", "result"}, nil,
+	)
+	reconnectActiveDesc = prometheus.NewDesc(
+		"fiozap_reconnects_in_progress",
+		"Supervisores de reconexao em execucao.",
+		nil, nil,
+	)
+)
+
+// Collector expoe estados das sessoes, envios por sessao e contadores de reconexao; registrar
+// uma vez (em main)
+func (m *Manager) Collector() prometheus.Collector {
+	return managerCollector{m}
+}
+
+// managerCollector le estados e envios das sessoes e contadores de reconexao a cada coleta. Os
+// envios por sessao ficam aqui, e nao num CounterVec, para a cardinalidade acompanhar as sessoes
+// existentes
+type managerCollector struct {
+	m *Manager
+}
+
+func (c managerCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- sessionsDesc
+	ch <- sessionMessagesSentDesc
+	ch <- reconnectAttemptsDesc
+	ch <- reconnectResultsDesc
+	ch <- reconnectActiveDesc
+}
+
+func (c managerCollector) Collect(ch chan<- prometheus.Metric) {
+	c.m.mu.RLock()
+	sessions := make([]*Session, 0, len(c.m.sessions))
+	for _, s := range c.m.sessions {
+		sessions = append(sessions, s)
+	}
+	c.m.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, s := range sessions {
+		counts[string(s.GetStatus())]++
+		for k, n := range s.sentCounts() {
+			ch <- prometheus.MustNewConstMetric(sessionMessagesSentDesc, prometheus.CounterValue, float64(n), s.Name, k.msgType, k.result)
+		}
+	}
+	for state, n := range counts {
+		ch <- prometheus.MustNewConstMetric(sessionsDesc, prometheus.GaugeValue, float64(n), state)
+	}
+
+	stats := c.m.ReconnectStats()
+	ch <- prometheus.MustNewConstMetric(reconnectAttemptsDesc, prometheus.CounterValue, float64(stats.Attempts))
+	ch <- prometheus.MustNewConstMetric(reconnectResultsDesc, prometheus.CounterValue, float64(stats.Successes), "success")
+	ch <- prometheus.MustNewConstMetric(reconnectResultsDesc, prometheus.CounterValue, float64(stats.Failures), "failure")
+	ch <- prometheus.MustNewConstMetric(reconnectActiveDesc, prometheus.GaugeValue, float64(stats.Active))
+}
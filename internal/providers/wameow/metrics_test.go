@@ -0,0 +1,97 @@
+package wameow
+
+import (
+	"reflect"
+	"testing"
+
+	"fiozap/internal/core"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMessageType(t *testing.T) {
+	user := types.NewJID("5511999999999", types.DefaultUserServer)
+
+	tests := []struct {
+		name string
+		to   types.JID
+		msg  *waE2E.Message
+		want string
+	}{
+		{"conversation", user, &waE2E.Message{Conversation: proto.String("hi")}, "text"},
+		{"extended text", user, &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{}}, "text"},
+		{"image", user, &waE2E.Message{ImageMessage: &waE2E.ImageMessage{}}, "image"},
+		{"view once video", user, &waE2E.Message{ViewOnceMessage: &waE2E.FutureProofMessage{Message: &waE2E.Message{VideoMessage: &waE2E.VideoMessage{}}}}, "video"},
+		{"view once v2 audio", user, &waE2E.Message{ViewOnceMessageV2: &waE2E.FutureProofMessage{Message: &waE2E.Message{AudioMessage: &waE2E.AudioMessage{}}}}, "audio"},
+		{"poll v3", user, &waE2E.Message{PollCreationMessageV3: &waE2E.PollCreationMessage{}}, "poll"},
+		{"revoke", user, &waE2E.Message{ProtocolMessage: &waE2E.ProtocolMessage{}}, "protocol"},
+		{"status overrides content", types.StatusBroadcastJID, &waE2E.Message{ImageMessage: &waE2E.ImageMessage{}}, "status"},
+		{"empty", user, &waE2E.Message{}, "other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := messageType(tt.to, tt.msg); got != tt.want {
+				t.Errorf("messageType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventType(t *testing.T) {
+	if got := eventType(&events.Message{}); got != "Message" {
+		t.Errorf("eventType() = %q, want Message", got)
+	}
+}
+
+func TestCollectorSessionMessagesSent(t *testing.T) {
+	vendas := &Session{Name: "vendas", status: core.SessionConnected}
+	vendas.recordSent("text", "success")
+	vendas.recordSent("text", "success")
+	vendas.recordSent("image", "error")
+	suporte := &Session{Name: "suporte", status: core.SessionIdle}
+	m := &Manager{sessions: map[string]*Session{"vendas": vendas, "suporte": suporte}}
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(m.Collector())
+	want := map[string]float64{
+		"vendas/text/success": 2,
+		"vendas/image/error":  1,
+	}
+	if got := gatherSessionSent(t, reg); !reflect.DeepEqual(got, want) {
+		t.Fatalf("series = %v, want %v", got, want)
+	}
+
+	// Sessao removida deixa de ser exportada
+	delete(m.sessions, "vendas")
+	if got := gatherSessionSent(t, reg); len(got) != 0 {
+		t.Fatalf("series after removal = %v, want none", got)
+	}
+}
+
+// gatherSessionSent le fiozap_session_messages_sent_total como sessao/tipo/resultado -> valor
+func gatherSessionSent(t *testing.T, reg *prometheus.Registry) map[string]float64 {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather: %v", err)
+	}
+	got := make(map[string]float64)
+	for _, f := range families {
+		if f.GetName() != "fiozap_session_messages_sent_total" {
+			continue
+		}
+		for _, metric := range f.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range metric.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			got[labels["session"]+"/"+labels["type"]+"/"+labels["result"]] = metric.GetCounter().GetValue()
+		}
+	}
+	return got
+}
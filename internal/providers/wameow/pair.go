@@ -0,0 +1,94 @@
+package wameow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"fiozap/internal/core"
+	"fiozap/internal/integrations/webhook"
+
+	"go.mau.fi/whatsmeow"
+)
+
+const (
+	// minPairingWindow tempo minimo restante na janela de login para reaproveita-la
+	minPairingWindow = 30 * time.Second
+	pairReadyTimeout = 15 * time.Second
+	pairMaxAttempts  = 3
+)
+
+// PairPhone gera codigo de pareamento de 8 caracteres como alternativa ao QR
+func (m *Manager) PairPhone(ctx context.Context, name, phone string) (*core.PairingCode, error) {
+	session, err := m.getSessionInternal(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Device.ID != nil {
+		return nil, fmt.Errorf("session %s already paired", name)
+	}
+
+	// Reabre o websocket de login se ele nao existe ou esta perto de expirar
+	if session.Client == nil || !session.Client.IsConnected() || time.Until(session.pairingExpiresAt()) < minPairingWindow {
+		if session.Client != nil {
+			session.Client.Disconnect()
+		}
+		if _, err := m.Connect(ctx, name); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := session.waitQRReady(ctx, pairReadyTimeout); err != nil {
+		return nil, err
+	}
+
+	_, platform := m.deviceProps(session)
+	var code string
+	for attempt := 1; attempt <= pairMaxAttempts; attempt++ {
+		code, err = session.Client.PairPhone(ctx, phone, true, platform.pairClient, platform.pairDisplay)
+		if err == nil {
+			break
+		}
+		if errors.Is(err, whatsmeow.ErrPhoneNumberTooShort) || errors.Is(err, whatsmeow.ErrPhoneNumberIsNotInternational) {
+			return nil, fmt.Errorf("invalid phone: %w", err)
+		}
+
+		m.log.Warn().Err(err).Str("name", name).Int("attempt", attempt).Msg("Pair phone failed")
+		if attempt < pairMaxAttempts {
+			select {
+			case <-time.After(time.Duration(attempt) * time.Second):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("pair phone failed: %w", err)
+	}
+
+	expiresAt := session.pairingExpiresAt()
+	session.setPairCode(code, expiresAt)
+	session.publishLogin(core.LoginEvent{Event: core.LoginEventPairingCode, Code: code, ExpiresAt: expiresAt})
+
+	m.log.Info().Str("name", name).Time("expires_at", expiresAt).Msg("Pairing code generated")
+	m.webhook.Dispatch(context.Background(), name, webhook.EventPairingCode, &webhook.PairingCodePayload{
+		Code:      code,
+		Phone:     phone,
+		ExpiresAt: expiresAt,
+	})
+
+	return &core.PairingCode{Code: code, ExpiresAt: expiresAt}, nil
+}
+
+// SubscribeLogin acompanha o fluxo de login da sessao (QR, codigo, timeout, sucesso, conexao)
+func (m *Manager) SubscribeLogin(name string) (<-chan core.LoginEvent, func(), error) {
+	session, err := m.getSessionInternal(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ch, cancel := session.subscribeLogin()
+	return ch, cancel, nil
+}
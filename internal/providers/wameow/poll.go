@@ -0,0 +1,174 @@
+package wameow
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"fiozap/internal/core"
+	"fiozap/internal/integrations/webhook"
+	"fiozap/internal/repository"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// GetPollResults retorna a contagem atual de votos de uma enquete
+func (m *Manager) GetPollResults(ctx context.Context, session, messageID string) (*core.PollResults, error) {
+	s, err := m.getSessionInternal(session)
+	if err != nil {
+		return nil, err
+	}
+
+	poll, err := m.polls.Get(ctx, s.ID, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load poll: %w", err)
+	}
+	if poll == nil {
+		return nil, fmt.Errorf("poll %s not found", messageID)
+	}
+
+	votes, err := m.polls.ListVotes(ctx, s.ID, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load poll votes: %w", err)
+	}
+
+	results := &core.PollResults{
+		MessageID: poll.MessageID,
+		ChatJID:   poll.ChatJID,
+		Question:  poll.Question,
+		Options:   make([]core.PollOptionResult, len(poll.Options)),
+	}
+	index := make(map[string]int, len(poll.Options))
+	for i, name := range poll.Options {
+		results.Options[i] = core.PollOptionResult{Name: name, Voters: []string{}}
+		index[name] = i
+	}
+
+	for _, v := range votes {
+		if len(v.Options) == 0 {
+			continue
+		}
+		results.TotalVoters++
+		for _, name := range v.Options {
+			if i, ok := index[name]; ok {
+				results.Options[i].Count++
+				results.Options[i].Voters = append(results.Options[i].Voters, v.VoterJID)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// handlePollMessage registra enquetes criadas e decifra votos recebidos
+func (m *Manager) handlePollMessage(ctx context.Context, session *Session, evt *events.Message) {
+	if poll := pollCreationFromMessage(evt.Message); poll != nil {
+		m.storePoll(ctx, session, evt.Info.Chat, evt.Info.Sender, evt.Info.ID, poll)
+		return
+	}
+
+	if evt.Message.GetPollUpdateMessage() != nil {
+		m.handlePollVote(ctx, session, evt)
+	}
+}
+
+// storePoll persiste a enquete para permitir decifrar e contabilizar votos
+func (m *Manager) storePoll(ctx context.Context, session *Session, chat, sender types.JID, messageID string, poll *waE2E.PollCreationMessage) {
+	options := make([]string, 0, len(poll.GetOptions()))
+	for _, opt := range poll.GetOptions() {
+		options = append(options, opt.GetOptionName())
+	}
+
+	model := &repository.PollModel{
+		SessionID:       session.ID,
+		MessageID:       messageID,
+		ChatJID:         chat.String(),
+		SenderJID:       repository.NullString(sender.ToNonAD().String()),
+		Question:        poll.GetName(),
+		Options:         options,
+		SelectableCount: int(poll.GetSelectableOptionsCount()),
+	}
+	if err := m.polls.Create(ctx, model); err != nil {
+		m.log.Error().Err(err).Str("name", session.Name).Str("poll", messageID).Msg("Failed to store poll")
+	}
+}
+
+// handlePollVote decifra um PollUpdateMessage e emite o evento PollVote normalizado
+func (m *Manager) handlePollVote(ctx context.Context, session *Session, evt *events.Message) {
+	if session.Client == nil {
+		return
+	}
+
+	pollID := evt.Message.GetPollUpdateMessage().GetPollCreationMessageKey().GetID()
+	vote, err := session.Client.DecryptPollVote(ctx, evt)
+	if err != nil {
+		m.log.Warn().Err(err).Str("name", session.Name).Str("poll", pollID).Msg("Failed to decrypt poll vote")
+		return
+	}
+
+	poll, err := m.polls.Get(ctx, session.ID, pollID)
+	if err != nil {
+		m.log.Error().Err(err).Str("name", session.Name).Str("poll", pollID).Msg("Failed to load poll")
+		return
+	}
+	if poll == nil {
+		m.log.Debug().Str("name", session.Name).Str("poll", pollID).Msg("Vote for unknown poll, skipping")
+		return
+	}
+
+	selected := matchPollOptions(poll.Options, vote.GetSelectedOptions())
+	voter := evt.Info.Sender.ToNonAD().String()
+
+	err = m.polls.UpsertVote(ctx, &repository.PollVoteModel{
+		SessionID: session.ID,
+		MessageID: pollID,
+		VoterJID:  voter,
+		Options:   selected,
+		VotedAt:   evt.Info.Timestamp,
+	})
+	if err != nil {
+		m.log.Error().Err(err).Str("name", session.Name).Str("poll", pollID).Msg("Failed to store poll vote")
+	}
+
+	m.log.Debug().Str("name", session.Name).Str("poll", pollID).Str("voter", voter).Msg("Poll vote received")
+	m.webhook.Dispatch(ctx, session.Name, webhook.EventPollVote, &webhook.PollVotePayload{
+		PollID:          pollID,
+		Chat:            evt.Info.Chat.String(),
+		Voter:           voter,
+		SelectedOptions: selected,
+		Timestamp:       evt.Info.Timestamp,
+	})
+}
+
+// pollCreationFromMessage retorna a enquete independente da versao do proto
+func pollCreationFromMessage(msg *waE2E.Message) *waE2E.PollCreationMessage {
+	switch {
+	case msg.GetPollCreationMessage() != nil:
+		return msg.GetPollCreationMessage()
+	case msg.GetPollCreationMessageV2() != nil:
+		return msg.GetPollCreationMessageV2()
+	case msg.GetPollCreationMessageV3() != nil:
+		return msg.GetPollCreationMessageV3()
+	case msg.GetPollCreationMessageV5() != nil:
+		return msg.GetPollCreationMessageV5()
+	}
+	return nil
+}
+
+// matchPollOptions converte os hashes SHA-256 do voto de volta para os nomes das opcoes
+func matchPollOptions(options []string, hashes [][]byte) []string {
+	optionHashes := whatsmeow.HashPollOptions(options)
+	selected := make([]string, 0, len(hashes))
+	for _, h := range hashes {
+		for i, oh := range optionHashes {
+			if bytes.Equal(h, oh) {
+				selected = append(selected, options[i])
+				break
+			}
+		}
+	}
+	return selected
+}
@@ -0,0 +1,88 @@
+package wameow
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"fiozap/internal/core"
+
+	"go.mau.fi/whatsmeow"
+)
+
+// validateProxy aceita http://, https:// e socks5:// com host e porta (vazio = conexao direta)
+func validateProxy(addr string) error {
+	if addr == "" {
+		return nil
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return fmt.Errorf("invalid proxy: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return fmt.Errorf("invalid proxy: unsupported scheme %q (use http, https or socks5)", u.Scheme)
+	}
+	if u.Hostname() == "" || u.Port() == "" {
+		return fmt.Errorf("invalid proxy: host and port are required")
+	}
+	return nil
+}
+
+// redactProxy esconde a senha do proxy para exibicao
+func redactProxy(addr string) string {
+	if addr == "" {
+		return ""
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return ""
+	}
+	return u.Redacted()
+}
+
+// applyProxy configura o proxy no client antes do Connect. Vale para o websocket
+// e para upload/download de midia. Sem proxy mantem o padrao do whatsmeow (https_proxy do ambiente)
+func applyProxy(client *whatsmeow.Client, addr string) error {
+	if addr == "" {
+		return nil
+	}
+	if err := client.SetProxyAddress(addr); err != nil {
+		return fmt.Errorf("invalid proxy: %w", err)
+	}
+	return nil
+}
+
+// SetProxy altera o proxy da sessao. Se estiver logada e conectada, reconecta pelo novo proxy;
+// caso contrario vale a partir da proxima conexao
+func (m *Manager) SetProxy(ctx context.Context, name, proxy string) error {
+	session, err := m.getSessionInternal(name)
+	if err != nil {
+		return err
+	}
+	if err := validateProxy(proxy); err != nil {
+		return err
+	}
+
+	if err := m.repo.UpdateProxy(ctx, session.ID, proxy); err != nil {
+		return fmt.Errorf("failed to save proxy: %w", err)
+	}
+	session.setProxy(proxy)
+	m.log.Info().Str("name", name).Str("proxy", redactProxy(proxy)).Msg("Session proxy updated")
+
+	client := session.Client
+	if client == nil || !client.IsConnected() || !client.IsLoggedIn() {
+		return nil
+	}
+
+	client.RemoveEventHandlers()
+	client.Disconnect()
+	session.setConnected(false)
+	m.setStatus(session, core.SessionReconnecting, "proxy changed")
+	if _, err := m.Connect(context.Background(), name); err != nil {
+		m.superviseReconnect(session, "proxy changed", false)
+		return fmt.Errorf("proxy saved but reconnect failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package wameow
+
+import (
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// messagePayload evento Message do whatsmeow com metadados normalizados.
+// Message ja vem desencapsulado de ViewOnceMessage/ViewOnceMessageV2/V2Extension
+type messagePayload struct {
+	*events.Message
+	MediaType string `json:"MediaType,omitempty"`
+	ViewOnce  bool   `json:"ViewOnce"`
+}
+
+// newMessagePayload monta o payload normalizado do evento Message
+func newMessagePayload(evt *events.Message) *messagePayload {
+	mediaType, viewOnce := describeMedia(evt.Message)
+	return &messagePayload{
+		Message:   evt,
+		MediaType: mediaType,
+		ViewOnce:  viewOnce || evt.IsViewOnce,
+	}
+}
+
+// describeMedia retorna o tipo de midia e se a propria midia esta marcada como visualizacao unica
+func describeMedia(msg *waE2E.Message) (string, bool) {
+	switch {
+	case msg.GetImageMessage() != nil:
+		return "image", msg.GetImageMessage().GetViewOnce()
+	case msg.GetVideoMessage() != nil:
+		if msg.GetVideoMessage().GetGifPlayback() {
+			return "gif", msg.GetVideoMessage().GetViewOnce()
+		}
+		return "video", msg.GetVideoMessage().GetViewOnce()
+	case msg.GetAudioMessage() != nil:
+		if msg.GetAudioMessage().GetPTT() {
+			return "ptt", msg.GetAudioMessage().GetViewOnce()
+		}
+		return "audio", msg.GetAudioMessage().GetViewOnce()
+	case msg.GetDocumentMessage() != nil:
+		return "document", false
+	case msg.GetStickerMessage() != nil:
+		return "sticker", false
+	}
+	return "", false
+}
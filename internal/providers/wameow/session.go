@@ -1,23 +1,75 @@
 package wameow
 
 import (
+	"context"
+	"crypto/subtle"
+	"fmt"
 	"sync"
+	"time"
+
+	"fiozap/internal/core"
 
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/types"
 )
 
+// pairingWindow tempo que o websocket de login fica aberto aguardando pareamento
+const pairingWindow = 160 * time.Second
+
 // Session representa uma sessao WhatsApp
 type Session struct {
-	ID        string
-	Name      string
-	Token     string
-	Client    *whatsmeow.Client
-	Device    *store.Device
-	connected bool
-	qrCode    string
-	jid       string
-	mu        sync.RWMutex
+	ID       string
+	Name     string
+	TenantID string
+	Client   *whatsmeow.Client
+	proxy    string
+	metadata map[string]string
+	tags     []string
+
+	// Exibidos no celular; vazios usam o padrao global
+	deviceName     string
+	devicePlatform string
+	Device         *store.Device
+	connected      bool
+	status         core.SessionStatus
+	qrCode         string
+	jid            string
+	mu             sync.RWMutex
+
+	// Politica de inatividade: desconecta apos idleTimeout sem uso da API (0 = nunca)
+	idleTimeout  time.Duration
+	lastActivity time.Time
+	wakeMu       sync.Mutex // serializa reconexoes sob demanda
+
+	// Envios por tipo e resultado, expostos por sessao no managerCollector
+	sent map[sentKey]uint64
+
+	// Diagnostico de conectividade
+	lastEventAt          time.Time
+	lastKeepAliveTimeout time.Time
+	lastSendError        string
+	lastSendErrorAt      time.Time
+
+	// Apenas hashes SHA-256 dos tokens ficam em memoria e no banco
+	tokenHash          string
+	tokenExpiresAt     time.Time
+	prevTokenHash      string
+	prevTokenExpiresAt time.Time
+
+	// Pareamento (QR ou codigo)
+	loginStartedAt    time.Time
+	qrReady           chan struct{}
+	pairCode          string
+	pairCodeExpiresAt time.Time
+	loginSubs         map[chan core.LoginEvent]struct{}
+
+	// Posse da sessao por esta instancia (lease no banco)
+	owned bool
+
+	// Supervisor de reconexao
+	supervisorCtx    context.Context
+	supervisorCancel context.CancelFunc
 }
 
 func (s *Session) IsConnected() bool {
@@ -32,6 +84,24 @@ func (s *Session) setConnected(v bool) {
 	s.connected = v
 }
 
+func (s *Session) GetStatus() core.SessionStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status
+}
+
+// setStatus troca o estado e retorna o anterior; changed e falso se ja estava no estado
+func (s *Session) setStatus(to core.SessionStatus) (from core.SessionStatus, changed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	from = s.status
+	if from == to {
+		return from, false
+	}
+	s.status = to
+	return from, true
+}
+
 func (s *Session) GetQRCode() string {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -48,6 +118,12 @@ func (s *Session) GetPhone() string {
 	if s.Client != nil && s.Client.Store.ID != nil {
 		return s.Client.Store.ID.User
 	}
+	// Sessao atendida por outra instancia: telefone vem do JID persistido
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if jid, err := types.ParseJID(s.jid); err == nil {
+		return jid.User
+	}
 	return ""
 }
 
@@ -67,10 +143,292 @@ func (s *Session) GetJID() string {
 	return s.jid
 }
 
-func (s *Session) GetToken() string {
-	return s.Token
+// CheckToken compara o hash do token em tempo constante, aceitando o token anterior durante a carencia
+func (s *Session) CheckToken(token string) bool {
+	hash := []byte(hashToken(token))
+	now := time.Now()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	current := subtle.ConstantTimeCompare(hash, []byte(s.tokenHash)) == 1 &&
+		(s.tokenExpiresAt.IsZero() || now.Before(s.tokenExpiresAt))
+	previous := s.prevTokenHash != "" &&
+		subtle.ConstantTimeCompare(hash, []byte(s.prevTokenHash)) == 1 &&
+		now.Before(s.prevTokenExpiresAt)
+	return current || previous
+}
+
+func (s *Session) setToken(hash string, expiresAt time.Time, prevHash string, prevExpiresAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokenHash = hash
+	s.tokenExpiresAt = expiresAt
+	s.prevTokenHash = prevHash
+	s.prevTokenExpiresAt = prevExpiresAt
 }
 
 func (s *Session) GetName() string {
 	return s.Name
 }
+
+func (s *Session) GetTenantID() string {
+	return s.TenantID
+}
+
+// GetProxy retorna o proxy da sessao com a senha mascarada
+func (s *Session) GetProxy() string {
+	return redactProxy(s.getProxy())
+}
+
+func (s *Session) getProxy() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.proxy
+}
+
+func (s *Session) setProxy(proxy string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.proxy = proxy
+}
+
+// GetMetadata retorna uma copia dos metadados da sessao
+func (s *Session) GetMetadata() map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	metadata := make(map[string]string, len(s.metadata))
+	for k, v := range s.metadata {
+		metadata[k] = v
+	}
+	return metadata
+}
+
+// GetTags retorna uma copia das tags da sessao
+func (s *Session) GetTags() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]string{}, s.tags...)
+}
+
+func (s *Session) setLabels(metadata map[string]string, tags []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metadata = metadata
+	s.tags = tags
+}
+
+func (s *Session) GetDeviceName() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.deviceName
+}
+
+func (s *Session) GetDevicePlatform() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.devicePlatform
+}
+
+// GetIdleTimeout retorna o tempo sem uso da API ate desconectar (0 = nunca)
+func (s *Session) GetIdleTimeout() time.Duration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.idleTimeout
+}
+
+func (s *Session) setIdleTimeout(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.idleTimeout = d
+}
+
+// touch registra uso da sessao pela API
+func (s *Session) touch() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastActivity = time.Now()
+}
+
+// idleSince retorna ha quanto tempo a sessao esta sem uso e a politica configurada
+func (s *Session) idleSince() (idle, timeout time.Duration) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return time.Since(s.lastActivity), s.idleTimeout
+}
+
+func (s *Session) getDeviceProps() (name, platform string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.deviceName, s.devicePlatform
+}
+
+// startPairing inicia uma nova janela de login (websocket aguardando QR ou codigo)
+func (s *Session) startPairing() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loginStartedAt = time.Now()
+	s.qrReady = make(chan struct{})
+	s.pairCode = ""
+	s.pairCodeExpiresAt = time.Time{}
+}
+
+// markQRReady sinaliza que o websocket de login recebeu o primeiro QR
+func (s *Session) markQRReady() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.qrReady == nil {
+		return
+	}
+	select {
+	case <-s.qrReady:
+	default:
+		close(s.qrReady)
+	}
+}
+
+// waitQRReady aguarda o primeiro QR da janela de login atual
+func (s *Session) waitQRReady(ctx context.Context, timeout time.Duration) error {
+	s.mu.RLock()
+	ready := s.qrReady
+	s.mu.RUnlock()
+	if ready == nil {
+		return fmt.Errorf("session is not waiting for pairing")
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-ready:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("timeout waiting for login websocket")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pairingExpiresAt retorna quando a janela de login atual expira
+func (s *Session) pairingExpiresAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.loginStartedAt.IsZero() {
+		return time.Time{}
+	}
+	return s.loginStartedAt.Add(pairingWindow)
+}
+
+func (s *Session) setPairCode(code string, expiresAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pairCode = code
+	s.pairCodeExpiresAt = expiresAt
+}
+
+// clearPairing encerra a janela de login atual
+func (s *Session) clearPairing() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.qrCode = ""
+	s.pairCode = ""
+	s.pairCodeExpiresAt = time.Time{}
+	s.loginStartedAt = time.Time{}
+}
+
+// GetPairCode retorna o codigo de pareamento ativo (vazio se expirado)
+func (s *Session) GetPairCode() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.pairCode == "" || time.Now().After(s.pairCodeExpiresAt) {
+		return ""
+	}
+	return s.pairCode
+}
+
+// subscribeLogin registra um ouvinte dos eventos de login. O ouvinte recebe o QR atual, se houver
+func (s *Session) subscribeLogin() (chan core.LoginEvent, func()) {
+	ch := make(chan core.LoginEvent, 8)
+
+	s.mu.Lock()
+	if s.loginSubs == nil {
+		s.loginSubs = make(map[chan core.LoginEvent]struct{})
+	}
+	s.loginSubs[ch] = struct{}{}
+	if s.qrCode != "" {
+		ch <- core.LoginEvent{Event: core.LoginEventCode, Code: s.qrCode}
+	}
+	if s.pairCode != "" && time.Now().Before(s.pairCodeExpiresAt) {
+		ch <- core.LoginEvent{Event: core.LoginEventPairingCode, Code: s.pairCode, ExpiresAt: s.pairCodeExpiresAt}
+	}
+	s.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.loginSubs, ch)
+			s.mu.Unlock()
+		})
+	}
+	return ch, cancel
+}
+
+// publishLogin envia o evento para todos os ouvintes sem bloquear; ouvintes lentos perdem eventos
+func (s *Session) publishLogin(evt core.LoginEvent) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for ch := range s.loginSubs {
+		select {
+		case ch <- evt:
+		default:
+		}
+	}
+}
+
+// startSupervisor reserva o supervisor de reconexao; falso se ja existe um rodando
+func (s *Session) startSupervisor() (context.Context, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.supervisorCancel != nil {
+		return nil, false
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s.supervisorCtx = ctx
+	s.supervisorCancel = cancel
+	return ctx, true
+}
+
+// releaseSupervisor libera a reserva feita por startSupervisor, se ainda for a mesma
+func (s *Session) releaseSupervisor(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.supervisorCtx == ctx {
+		s.supervisorCancel()
+		s.supervisorCtx = nil
+		s.supervisorCancel = nil
+	}
+}
+
+func (s *Session) isOwned() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.owned
+}
+
+func (s *Session) setOwned(v bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.owned = v
+}
+
+// stopSupervisor cancela o supervisor de reconexao, se houver
+func (s *Session) stopSupervisor() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.supervisorCancel != nil {
+		s.supervisorCancel()
+		s.supervisorCtx = nil
+		s.supervisorCancel = nil
+	}
+}
@@ -0,0 +1,75 @@
+package wameow
+
+import (
+	"context"
+	"fmt"
+
+	"fiozap/internal/core"
+)
+
+// errShuttingDown retornado a envios e conexoes durante o shutdown
+var errShuttingDown = fmt.Errorf("server shutting down")
+
+// Shutdown encerra o Manager: recusa novos envios e conexoes, para supervisores e lacos de posse,
+// desconecta os clients e grava o estado para que as sessoes online reconectem no proximo boot
+// (ou sejam assumidas por outra replica). Sessoes nao processadas ate o prazo ficam como estavam
+// no banco e tambem reconectam no boot
+func (m *Manager) Shutdown(ctx context.Context) error {
+	if !m.closing.CompareAndSwap(false, true) {
+		return nil
+	}
+	m.cancel()
+
+	m.mu.RLock()
+	sessions := make([]*Session, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		sessions = append(sessions, s)
+	}
+	m.mu.RUnlock()
+
+	for _, s := range sessions {
+		s.stopSupervisor()
+	}
+
+	var stopped, resumable int
+	for _, session := range sessions {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("shutdown interrupted after %d sessions: %w", stopped, err)
+		}
+		if !session.isOwned() {
+			continue
+		}
+
+		online := false
+		switch session.GetStatus() {
+		case core.SessionConnected, core.SessionConnecting, core.SessionReconnecting:
+			online = session.Device != nil && session.Device.ID != nil
+		}
+
+		if session.Client != nil {
+			session.Client.RemoveEventHandlers()
+			session.Client.Disconnect()
+		}
+		session.setConnected(false)
+
+		if online {
+			if err := m.repo.MarkShutdown(ctx, session.ID); err != nil {
+				m.log.Error().Err(err).Str("name", session.Name).Msg("Failed to record shutdown state")
+			}
+			m.setStatus(session, core.SessionDisconnected, "server shutdown")
+			resumable++
+		} else {
+			m.updateSessionInDB(session)
+			m.setStatus(session, idleStatus(session), "server shutdown")
+		}
+		stopped++
+	}
+
+	// Libera as posses para outra replica assumir sem esperar o TTL
+	if err := m.leases.ReleaseAll(ctx, m.opts.InstanceID); err != nil {
+		m.log.Error().Err(err).Msg("Failed to release session leases")
+	}
+
+	m.log.Info().Int("sessions", stopped).Int("resume_on_boot", resumable).Msg("Sessions disconnected for shutdown")
+	return nil
+}
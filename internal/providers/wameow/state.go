@@ -0,0 +1,62 @@
+package wameow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"fiozap/internal/core"
+	"fiozap/internal/integrations/webhook"
+)
+
+// setStatus aplica a transicao de estado, persiste no historico e emite o evento SessionStatus
+func (m *Manager) setStatus(session *Session, to core.SessionStatus, reason string) {
+	from, changed := session.setStatus(to)
+	if !changed {
+		return
+	}
+
+	if err := m.repo.UpdateStatus(context.Background(), session.ID, string(from), string(to), reason); err != nil {
+		m.log.Error().Err(err).Str("name", session.Name).Str("status", string(to)).Msg("Failed to persist session status")
+	}
+
+	m.log.Debug().Str("name", session.Name).Str("from", string(from)).Str("to", string(to)).Str("reason", reason).Msg("Session status changed")
+	m.webhook.Dispatch(context.Background(), session.Name, webhook.EventSessionStatus, &webhook.SessionStatusPayload{
+		Status:         string(to),
+		PreviousStatus: string(from),
+		Reason:         reason,
+		Timestamp:      time.Now(),
+	})
+}
+
+// idleStatus estado de uma sessao parada: desconectada se pareada, criada se nunca pareou
+func idleStatus(session *Session) core.SessionStatus {
+	if session.Device != nil && session.Device.ID != nil {
+		return core.SessionDisconnected
+	}
+	return core.SessionCreated
+}
+
+// GetSessionHistory lista as transicoes de estado da sessao, mais recentes primeiro
+func (m *Manager) GetSessionHistory(ctx context.Context, name string, limit int) ([]core.SessionStatusChange, error) {
+	session, err := m.getSessionInternal(name)
+	if err != nil {
+		return nil, err
+	}
+
+	models, err := m.repo.ListStatusHistory(ctx, session.ID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list session history: %w", err)
+	}
+
+	result := make([]core.SessionStatusChange, len(models))
+	for i, h := range models {
+		result[i] = core.SessionStatusChange{
+			From:   core.SessionStatus(h.FromStatus.String),
+			To:     core.SessionStatus(h.ToStatus),
+			Reason: h.Reason.String,
+			At:     h.CreatedAt,
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,180 @@
+package wameow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"fiozap/internal/core"
+	"fiozap/internal/integrations/webhook"
+	"fiozap/internal/repository"
+
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+// SendTextStatus publica status de texto, para a audiencia da privacidade de status da conta
+func (m *Manager) SendTextStatus(ctx context.Context, session, text string, backgroundARGB, textARGB uint32, font int32, audience *core.StatusAudience) (*core.MessageResponse, error) {
+	ctx, span := startSpan(ctx, "SendTextStatus", session)
+	defer span.End()
+
+	if err := validateAudience(audience); err != nil {
+		return nil, err
+	}
+	client, err := m.getClient(session)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.sendStatus(ctx, session, client, &waE2E.Message{
+		ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+			Text:           proto.String(text),
+			BackgroundArgb: proto.Uint32(backgroundARGB),
+			TextArgb:       proto.Uint32(textARGB),
+			Font:           waE2E.ExtendedTextMessage_FontType(font).Enum(),
+		},
+	})
+}
+
+// SendImageStatus publica status de imagem
+func (m *Manager) SendImageStatus(ctx context.Context, session string, data []byte, caption, mimeType string, audience *core.StatusAudience) (*core.MessageResponse, error) {
+	ctx, span := startSpan(ctx, "SendImageStatus", session)
+	defer span.End()
+
+	if err := validateAudience(audience); err != nil {
+		return nil, err
+	}
+	client, err := m.getClient(session)
+	if err != nil {
+		return nil, err
+	}
+
+	uploaded, err := m.upload(ctx, client, data, whatsmeow.MediaImage)
+	if err != nil {
+		return nil, fmt.Errorf("upload failed: %w", err)
+	}
+	return m.sendStatus(ctx, session, client, imageMessage(uploaded, len(data), caption, mimeType))
+}
+
+// SendVideoStatus publica status de video
+func (m *Manager) SendVideoStatus(ctx context.Context, session string, data []byte, caption, mimeType string, audience *core.StatusAudience) (*core.MessageResponse, error) {
+	ctx, span := startSpan(ctx, "SendVideoStatus", session)
+	defer span.End()
+
+	if err := validateAudience(audience); err != nil {
+		return nil, err
+	}
+	client, err := m.getClient(session)
+	if err != nil {
+		return nil, err
+	}
+
+	uploaded, err := m.upload(ctx, client, data, whatsmeow.MediaVideo)
+	if err != nil {
+		return nil, fmt.Errorf("upload failed: %w", err)
+	}
+	return m.sendStatus(ctx, session, client, videoMessage(uploaded, len(data), caption, mimeType))
+}
+
+// validateAudience recusa audiencia propria: o whatsmeow so publica status pelo SendMessage, que
+// usa a privacidade de status da conta, e nao tem API publica para escolher os destinatarios
+func validateAudience(audience *core.StatusAudience) error {
+	if audience != nil {
+		return errors.New("invalid audience: custom status audiences are not supported, statuses follow the account status privacy")
+	}
+	return nil
+}
+
+// sendStatus publica no status@broadcast
+func (m *Manager) sendStatus(ctx context.Context, session string, client *whatsmeow.Client, msg *waE2E.Message) (*core.MessageResponse, error) {
+	resp, err := m.sendMessage(ctx, session, client, types.StatusBroadcastJID, msg)
+	if err != nil {
+		return nil, fmt.Errorf("send failed: %w", err)
+	}
+	return &core.MessageResponse{ID: resp.ID, Timestamp: resp.Timestamp}, nil
+}
+
+// ListStatuses lista status recebidos, mais recentes primeiro
+func (m *Manager) ListStatuses(ctx context.Context, session, sender string, limit int) ([]core.StatusUpdate, error) {
+	s, err := m.getSessionInternal(session)
+	if err != nil {
+		return nil, err
+	}
+
+	senderJID := ""
+	if sender != "" {
+		senderJID = parseJID(sender).String()
+	}
+
+	models, err := m.statuses.List(ctx, s.ID, senderJID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statuses: %w", err)
+	}
+
+	result := make([]core.StatusUpdate, len(models))
+	for i, st := range models {
+		result[i] = core.StatusUpdate{
+			ID:        st.MessageID,
+			SenderJID: st.SenderJID,
+			Type:      st.Type,
+			Body:      st.Body.String,
+			MimeType:  st.MimeType.String,
+			Timestamp: st.Timestamp,
+		}
+	}
+	return result, nil
+}
+
+// handleStatusMessage persiste status recebidos e emite o evento Status normalizado
+func (m *Manager) handleStatusMessage(ctx context.Context, session *Session, evt *events.Message) {
+	// Revogacoes e outras mensagens de protocolo nao sao status
+	if evt.Message.GetProtocolMessage() != nil || evt.Info.IsFromMe {
+		return
+	}
+
+	statusType, body, mimeType := describeStatus(evt.Message)
+	sender := evt.Info.Sender.ToNonAD().String()
+
+	err := m.statuses.Create(ctx, &repository.StatusModel{
+		SessionID: session.ID,
+		MessageID: evt.Info.ID,
+		SenderJID: sender,
+		Type:      statusType,
+		Body:      repository.NullString(body),
+		MimeType:  repository.NullString(mimeType),
+		Timestamp: evt.Info.Timestamp,
+	})
+	if err != nil {
+		m.log.Error().Err(err).Str("name", session.Name).Str("id", evt.Info.ID).Msg("Failed to store status")
+	}
+
+	m.log.Debug().Str("name", session.Name).Str("from", sender).Str("type", statusType).Msg("Status received")
+	m.webhook.Dispatch(ctx, session.Name, webhook.EventStatus, &webhook.StatusPayload{
+		ID:        evt.Info.ID,
+		Sender:    sender,
+		Type:      statusType,
+		Body:      body,
+		MimeType:  mimeType,
+		Timestamp: evt.Info.Timestamp,
+	})
+}
+
+// describeStatus retorna tipo, texto/legenda e mimetype do conteudo do status
+func describeStatus(msg *waE2E.Message) (statusType, body, mimeType string) {
+	switch {
+	case msg.GetExtendedTextMessage() != nil:
+		return "text", msg.GetExtendedTextMessage().GetText(), ""
+	case msg.GetConversation() != "":
+		return "text", msg.GetConversation(), ""
+	case msg.GetImageMessage() != nil:
+		return "image", msg.GetImageMessage().GetCaption(), msg.GetImageMessage().GetMimetype()
+	case msg.GetVideoMessage() != nil:
+		return "video", msg.GetVideoMessage().GetCaption(), msg.GetVideoMessage().GetMimetype()
+	case msg.GetAudioMessage() != nil:
+		return "audio", "", msg.GetAudioMessage().GetMimetype()
+	}
+	return "unknown", "", ""
+}
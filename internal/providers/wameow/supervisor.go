@@ -0,0 +1,226 @@
+package wameow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"fiozap/internal/core"
+	"fiozap/internal/integrations/webhook"
+)
+
+// reconnectConnectTimeout tempo que uma tentativa aguarda o evento Connected
+const reconnectConnectTimeout = 30 * time.Second
+
+// Resultados reportados no evento Reconnect
+const (
+	reconnectScheduled = "scheduled"
+	reconnectConnected = "connected"
+	reconnectStopped   = "stopped"
+)
+
+// ReconnectStats contadores acumulados do supervisor de reconexao
+type ReconnectStats struct {
+	Attempts  int64
+	Successes int64
+	Failures  int64
+	Active    int64
+}
+
+type reconnectCounters struct {
+	attempts  atomic.Int64
+	successes atomic.Int64
+	failures  atomic.Int64
+	active    atomic.Int64
+}
+
+// ReconnectStats retorna os contadores do supervisor de reconexao
+func (m *Manager) ReconnectStats() ReconnectStats {
+	return ReconnectStats{
+		Attempts:  m.reconnects.attempts.Load(),
+		Successes: m.reconnects.successes.Load(),
+		Failures:  m.reconnects.failures.Load(),
+		Active:    m.reconnects.active.Load(),
+	}
+}
+
+// superviseReconnect inicia o supervisor da sessao se ainda nao estiver rodando.
+// immediate faz a primeira tentativa sem espera (reconexao no boot)
+func (m *Manager) superviseReconnect(session *Session, reason string, immediate bool) {
+	ctx, ok := session.startSupervisor()
+	if !ok {
+		return
+	}
+	go m.runSupervisor(ctx, session, reason, immediate)
+}
+
+// resume retoma uma sessao que estava online (boot ou failover); o estado passa a reconnecting
+// para o supervisor nao descartar sessoes desligadas pelo shutdown
+func (m *Manager) resume(session *Session, reason string) {
+	m.setStatus(session, core.SessionReconnecting, reason)
+	m.superviseReconnect(session, reason, true)
+}
+
+// forceReconnect derruba a conexao atual e entrega a sessao ao supervisor
+func (m *Manager) forceReconnect(session *Session, reason string) {
+	if client := session.Client; client != nil {
+		client.Disconnect()
+	}
+	session.setConnected(false)
+	m.setStatus(session, core.SessionReconnecting, reason)
+	m.superviseReconnect(session, reason, false)
+}
+
+func (m *Manager) runSupervisor(ctx context.Context, session *Session, reason string, immediate bool) {
+	m.reconnects.active.Add(1)
+	defer m.reconnects.active.Add(-1)
+	defer session.releaseSupervisor(ctx)
+
+	for attempt := 1; ; attempt++ {
+		if !m.shouldReconnect(session) {
+			m.reportReconnect(session, attempt-1, reconnectStopped, string(session.GetStatus()), 0)
+			return
+		}
+
+		delay := time.Duration(0)
+		if !immediate || attempt > 1 {
+			delay = m.reconnectDelay(attempt)
+		}
+
+		m.setStatus(session, core.SessionReconnecting, reason)
+		m.reportReconnect(session, attempt, reconnectScheduled, reason, delay)
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+
+		if !m.shouldReconnect(session) {
+			m.reportReconnect(session, attempt, reconnectStopped, string(session.GetStatus()), 0)
+			return
+		}
+		if session.IsConnected() {
+			m.reconnects.successes.Add(1)
+			m.reportReconnect(session, attempt, reconnectConnected, "", 0)
+			return
+		}
+
+		if !m.acquireReconnectSlot(ctx) {
+			return
+		}
+		m.reconnects.attempts.Add(1)
+		m.log.Info().Str("name", session.Name).Int("attempt", attempt).Msg("Reconnecting session")
+		_, err := m.Connect(ctx, session.Name)
+		if err == nil {
+			err = m.waitConnected(ctx, session)
+		}
+		m.releaseReconnectSlot()
+
+		if err == nil {
+			m.reconnects.successes.Add(1)
+			m.reportReconnect(session, attempt, reconnectConnected, "", 0)
+			return
+		}
+
+		if errors.Is(err, errNotOwner) {
+			m.reportReconnect(session, attempt, reconnectStopped, err.Error(), 0)
+			return
+		}
+
+		m.reconnects.failures.Add(1)
+		m.log.Warn().Err(err).Str("name", session.Name).Int("attempt", attempt).Msg("Reconnect attempt failed")
+		reason = err.Error()
+	}
+}
+
+// shouldReconnect falso para sessoes removidas, nao pareadas, deslogadas, banidas ou paradas manualmente
+func (m *Manager) shouldReconnect(session *Session) bool {
+	m.mu.RLock()
+	current, exists := m.sessions[session.Name]
+	m.mu.RUnlock()
+	if !exists || current != session {
+		return false
+	}
+
+	if session.Device == nil || session.Device.ID == nil {
+		return false
+	}
+
+	switch session.GetStatus() {
+	case core.SessionLoggedOut, core.SessionBanned, core.SessionDisconnected, core.SessionCreated:
+		return false
+	}
+	return true
+}
+
+// waitConnected aguarda o login apos client.Connect; ConnectFailure e tratado como falha
+func (m *Manager) waitConnected(ctx context.Context, session *Session) error {
+	timeout := time.NewTimer(reconnectConnectTimeout)
+	defer timeout.Stop()
+	tick := time.NewTicker(500 * time.Millisecond)
+	defer tick.Stop()
+
+	for {
+		if session.IsConnected() {
+			return nil
+		}
+		switch session.GetStatus() {
+		case core.SessionFailed, core.SessionLoggedOut, core.SessionBanned:
+			return fmt.Errorf("connection %s", session.GetStatus())
+		}
+
+		select {
+		case <-tick.C:
+		case <-timeout.C:
+			return fmt.Errorf("timeout waiting for connection")
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// reconnectDelay backoff exponencial com jitter de +-20%
+func (m *Manager) reconnectDelay(attempt int) time.Duration {
+	delay := m.opts.ReconnectBaseDelay
+	for i := 1; i < attempt && delay < m.opts.ReconnectMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > m.opts.ReconnectMaxDelay {
+		delay = m.opts.ReconnectMaxDelay
+	}
+	jitter := time.Duration((rand.Float64()*0.4 - 0.2) * float64(delay))
+	return delay + jitter
+}
+
+func (m *Manager) acquireReconnectSlot(ctx context.Context) bool {
+	select {
+	case m.reconnectSlots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (m *Manager) releaseReconnectSlot() {
+	<-m.reconnectSlots
+}
+
+func (m *Manager) reportReconnect(session *Session, attempt int, result, reason string, delay time.Duration) {
+	payload := &webhook.ReconnectPayload{
+		Attempt: attempt,
+		Result:  result,
+		Reason:  reason,
+	}
+	if result == reconnectScheduled {
+		next := time.Now().Add(delay)
+		payload.NextAttemptAt = &next
+		m.log.Info().Str("name", session.Name).Int("attempt", attempt).Dur("delay", delay).Str("reason", reason).Msg("Reconnect scheduled")
+	} else {
+		m.log.Info().Str("name", session.Name).Int("attempts", attempt).Str("result", result).Str("reason", reason).Msg("Reconnect supervisor finished")
+	}
+	m.webhook.Dispatch(context.Background(), session.Name, webhook.EventReconnect, payload)
+}
@@ -0,0 +1,81 @@
+package wameow
+
+import (
+	"testing"
+	"time"
+
+	"fiozap/internal/core"
+
+	"go.mau.fi/whatsmeow/store"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestReconnectDelay(t *testing.T) {
+	m := &Manager{opts: Options{ReconnectBaseDelay: 2 * time.Second, ReconnectMaxDelay: time.Minute}}
+
+	tests := []struct {
+		attempt int
+		base    time.Duration
+	}{
+		{1, 2 * time.Second},
+		{2, 4 * time.Second},
+		{3, 8 * time.Second},
+		{5, 32 * time.Second},
+		{6, time.Minute},
+		{50, time.Minute},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			got := m.reconnectDelay(tt.attempt)
+			lo := time.Duration(float64(tt.base) * 0.8)
+			hi := time.Duration(float64(tt.base) * 1.2)
+			if got < lo || got > hi {
+				t.Fatalf("reconnectDelay(%d) = %v, want within [%v, %v]", tt.attempt, got, lo, hi)
+			}
+		}
+	}
+}
+
+func TestShouldReconnect(t *testing.T) {
+	paired := func() *store.Device {
+		return &store.Device{ID: &types.JID{User: "5511999999999", Server: types.DefaultUserServer}}
+	}
+
+	tests := []struct {
+		name       string
+		device     *store.Device
+		status     core.SessionStatus
+		registered bool
+		want       bool
+	}{
+		{"reconnecting", paired(), core.SessionReconnecting, true, true},
+		{"failed", paired(), core.SessionFailed, true, true},
+		{"removed", paired(), core.SessionReconnecting, false, false},
+		{"never paired", &store.Device{}, core.SessionReconnecting, true, false},
+		{"no device", nil, core.SessionReconnecting, true, false},
+		{"logged out", paired(), core.SessionLoggedOut, true, false},
+		{"banned", paired(), core.SessionBanned, true, false},
+		{"stopped manually", paired(), core.SessionDisconnected, true, false},
+		{"created", paired(), core.SessionCreated, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &Session{Name: "s1", Device: tt.device, status: tt.status}
+			m := &Manager{sessions: map[string]*Session{}}
+			if tt.registered {
+				m.sessions[session.Name] = session
+			}
+			if got := m.shouldReconnect(session); got != tt.want {
+				t.Errorf("shouldReconnect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShouldReconnectReplacedSession(t *testing.T) {
+	old := &Session{Name: "s1", Device: &store.Device{ID: &types.JID{User: "1", Server: types.DefaultUserServer}}, status: core.SessionReconnecting}
+	m := &Manager{sessions: map[string]*Session{"s1": {Name: "s1"}}}
+	if m.shouldReconnect(old) {
+		t.Error("supervisor of a replaced session must stop")
+	}
+}
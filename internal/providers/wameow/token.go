@@ -0,0 +1,74 @@
+package wameow
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"fiozap/internal/core"
+	"fiozap/internal/repository"
+)
+
+// maxTokenGrace carencia maxima do token anterior apos rotacao
+const maxTokenGrace = 7 * 24 * time.Hour
+
+func generateToken() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// hashToken retorna o SHA-256 do token em hex, formato guardado em "tokenHash"
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// RotateToken gera novo token; o anterior continua valido ate o fim da carencia
+func (m *Manager) RotateToken(ctx context.Context, name string, grace time.Duration, expiresAt time.Time) (*core.SessionToken, error) {
+	session, err := m.getSessionInternal(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if grace < 0 || grace > maxTokenGrace {
+		return nil, fmt.Errorf("invalid grace period: must be between 0 and %s", maxTokenGrace)
+	}
+	if !expiresAt.IsZero() && !expiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("invalid expiration: must be in the future")
+	}
+
+	token := generateToken()
+	hash := hashToken(token)
+
+	var prevHash string
+	var prevExpiresAt time.Time
+	if grace > 0 {
+		session.mu.RLock()
+		prevHash = session.tokenHash
+		prevExpiresAt = time.Now().Add(grace)
+		// A carencia nunca estende a validade original do token anterior
+		if !session.tokenExpiresAt.IsZero() && session.tokenExpiresAt.Before(prevExpiresAt) {
+			prevExpiresAt = session.tokenExpiresAt
+		}
+		session.mu.RUnlock()
+	}
+
+	err = m.repo.UpdateToken(ctx, &repository.SessionModel{
+		ID:                     session.ID,
+		TokenHash:              hash,
+		TokenExpiresAt:         repository.NullTime(expiresAt),
+		PreviousTokenHash:      repository.NullString(prevHash),
+		PreviousTokenExpiresAt: repository.NullTime(prevExpiresAt),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save token: %w", err)
+	}
+	session.setToken(hash, expiresAt, prevHash, prevExpiresAt)
+
+	m.log.Info().Str("name", name).Dur("grace", grace).Msg("Session token rotated")
+	return &core.SessionToken{Token: token, ExpiresAt: expiresAt, PreviousExpiresAt: prevExpiresAt}, nil
+}
@@ -0,0 +1,18 @@
+package wameow
+
+import (
+	"context"
+
+	"fiozap/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = tracing.Tracer("wameow")
+
+// startSpan abre o span de uma chamada ao provider
+func startSpan(ctx context.Context, op, session string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attribute.String("fiozap.session", session))
+	return tracer.Start(ctx, "wameow."+op, trace.WithAttributes(attrs...))
+}
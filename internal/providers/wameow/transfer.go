@@ -0,0 +1,316 @@
+package wameow
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"fiozap/internal/core"
+	"fiozap/internal/integrations/webhook"
+	"fiozap/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	archiveMagic         = "FZSA"
+	archiveVersion       = 1
+	archiveSaltSize      = 16
+	archiveKDFIterations = 600000
+	minPassphraseLength  = 12
+	maxArchiveSize       = 64 << 20 // descompactado
+)
+
+// sessionArchive conteudo do arquivo de exportacao (gzip + AES-256-GCM com chave PBKDF2 da senha)
+type sessionArchive struct {
+	Version    int                     `json:"version"`
+	ExportedAt time.Time               `json:"exportedAt"`
+	Source     string                  `json:"source"`   // instancia de origem
+	Detached   bool                    `json:"detached"` // sessao desligada na origem ao exportar
+	Session    archivedSession         `json:"session"`
+	Webhook    *webhook.ExportedConfig `json:"webhook,omitempty"`
+	Device     repository.DeviceData   `json:"device"`
+}
+
+type archivedSession struct {
+	Name           string            `json:"name"`
+	TenantID       string            `json:"tenantId,omitempty"`
+	Proxy          string            `json:"proxy,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+	Tags           []string          `json:"tags,omitempty"`
+	DeviceName     string            `json:"deviceName,omitempty"`
+	DevicePlatform string            `json:"devicePlatform,omitempty"`
+	IdleTimeout    int               `json:"idleTimeout,omitempty"` // segundos
+	TokenHash      string            `json:"tokenHash"`
+	TokenExpiresAt time.Time         `json:"tokenExpiresAt,omitempty"`
+	JID            string            `json:"jid"`
+	PushName       string            `json:"pushName,omitempty"`
+}
+
+// ExportSession exporta a sessao pareada (dados do FioZap, webhook e chaves do whatsmeow) num
+// arquivo cifrado. Com detach a sessao e desconectada aqui (apos selar o arquivo) e nao
+// reconecta sozinha, para que apenas o destino fique online
+func (m *Manager) ExportSession(ctx context.Context, name, passphrase string, detach bool) (*core.SessionArchive, error) {
+	session, err := m.getSessionInternal(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(passphrase) < minPassphraseLength {
+		return nil, fmt.Errorf("invalid passphrase: at least %d characters", minPassphraseLength)
+	}
+	if session.Device == nil || session.Device.ID == nil {
+		return nil, fmt.Errorf("session %s not paired", name)
+	}
+
+	jid := session.Device.ID.String()
+	device, err := m.transfer.ExportDevice(ctx, jid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export device: %w", err)
+	}
+
+	session.mu.RLock()
+	archived := archivedSession{
+		Name:           session.Name,
+		TenantID:       session.TenantID,
+		Proxy:          session.proxy,
+		Metadata:       session.metadata,
+		Tags:           session.tags,
+		DeviceName:     session.deviceName,
+		DevicePlatform: session.devicePlatform,
+		IdleTimeout:    int(session.idleTimeout / time.Second),
+		TokenHash:      session.tokenHash,
+		TokenExpiresAt: session.tokenExpiresAt,
+		JID:            jid,
+	}
+	session.mu.RUnlock()
+	archived.PushName = session.Device.PushName
+
+	archive := &sessionArchive{
+		Version:    archiveVersion,
+		ExportedAt: time.Now().UTC(),
+		Source:     m.opts.InstanceID,
+		Detached:   detach,
+		Session:    archived,
+		Webhook:    m.webhook.ExportConfig(name),
+		Device:     device,
+	}
+	data, err := sealArchive(archive, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	// Desliga so depois do arquivo pronto: uma falha acima deixa o numero online aqui
+	if detach {
+		session.stopSupervisor()
+		if session.Client != nil {
+			session.Client.Disconnect()
+			session.setConnected(false)
+		}
+		m.updateSessionInDB(session)
+		m.setStatus(session, core.SessionDisconnected, "exported")
+		m.release(session)
+	}
+
+	m.log.Info().Str("name", name).Bool("detached", detach).Int("bytes", len(data)).Msg("Session exported")
+	return &core.SessionArchive{Data: data, ExportedAt: archive.ExportedAt, Detached: detach}, nil
+}
+
+// ImportSession cria a sessao a partir de um arquivo exportado. Recusa se o numero ja esta nesta
+// instalacao ou, salvo force, se a origem nao foi desligada na exportacao (poderia estar online)
+func (m *Manager) ImportSession(ctx context.Context, data []byte, passphrase string, force bool) (core.Session, error) {
+	archive, err := openArchive(data, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if !archive.Detached && !force {
+		return nil, fmt.Errorf("session may be live elsewhere: archive was exported without Detach (disconnect the source and retry with Force)")
+	}
+
+	s := archive.Session
+	if s.Name == "" || s.JID == "" || s.TokenHash == "" {
+		return nil, fmt.Errorf("invalid archive: missing session data")
+	}
+
+	// Reserva nome e numero sob o lock; o I/O abaixo roda sem segurar m.mu
+	if err := m.reserveImport(s.Name, s.JID); err != nil {
+		return nil, err
+	}
+	defer m.releaseImport(s.Name)
+
+	// Tenant de outra instalacao pode nao existir aqui: a sessao fica com o super-admin
+	tenantID := s.TenantID
+	if tenantID != "" {
+		if tenant, err := m.tenants.Get(ctx, tenantID); err != nil || tenant == nil {
+			tenantID = ""
+		}
+	}
+
+	model := &repository.SessionModel{
+		ID:             uuid.New().String(),
+		Name:           s.Name,
+		TenantID:       repository.NullString(tenantID),
+		Proxy:          repository.NullString(s.Proxy),
+		Metadata:       s.Metadata,
+		Tags:           s.Tags,
+		DeviceName:     repository.NullString(s.DeviceName),
+		DevicePlatform: repository.NullString(s.DevicePlatform),
+		IdleTimeout:    s.IdleTimeout,
+		TokenHash:      s.TokenHash,
+		TokenExpiresAt: repository.NullTime(s.TokenExpiresAt),
+		JID:            repository.NullString(s.JID),
+		PushName:       repository.NullString(s.PushName),
+		Status:         string(core.SessionDisconnected),
+	}
+	if err := m.transfer.ImportSession(ctx, model, archive.Device); err != nil {
+		return nil, err
+	}
+
+	session := m.newSessionFromModel(model)
+	if session.Device == nil || session.Device.ID == nil {
+		return nil, fmt.Errorf("imported device %s could not be loaded", s.JID)
+	}
+	m.mu.Lock()
+	if existing, ok := m.sessions[s.Name]; ok {
+		// A sincronizacao ja carregou a linha recem-criada do banco
+		session = existing
+	} else {
+		m.sessions[s.Name] = session
+	}
+	m.mu.Unlock()
+	if archive.Webhook != nil {
+		m.webhook.ImportConfig(s.Name, archive.Webhook)
+	}
+
+	m.log.Info().Str("name", s.Name).Str("source", archive.Source).Time("exported_at", archive.ExportedAt).Msg("Session imported")
+	return session, nil
+}
+
+// reserveImport bloqueia nome e numero contra outra criacao ou importacao ate releaseImport
+func (m *Manager) reserveImport(name, jid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.sessions[name]; exists {
+		return fmt.Errorf("session %s already exists", name)
+	}
+	for other, otherJID := range m.importing {
+		if other == name {
+			return fmt.Errorf("session %s already exists", name)
+		}
+		if otherJID == jid {
+			return fmt.Errorf("session is live elsewhere: number already used by session %s", other)
+		}
+	}
+	for _, other := range m.sessions {
+		if other.GetJID() == jid {
+			return fmt.Errorf("session is live elsewhere: number already used by session %s", other.Name)
+		}
+	}
+	m.importing[name] = jid
+	return nil
+}
+
+func (m *Manager) releaseImport(name string) {
+	m.mu.Lock()
+	delete(m.importing, name)
+	m.mu.Unlock()
+}
+
+func archiveKey(passphrase string, salt []byte) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, passphrase, salt, archiveKDFIterations, 32)
+}
+
+// sealArchive formato: magic | salt | nonce | AES-256-GCM(gzip(json))
+func sealArchive(archive *sessionArchive, passphrase string) ([]byte, error) {
+	var plain bytes.Buffer
+	zw := gzip.NewWriter(&plain)
+	if err := json.NewEncoder(zw).Encode(archive); err != nil {
+		return nil, fmt.Errorf("failed to encode archive: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress archive: %w", err)
+	}
+
+	salt := make([]byte, archiveSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key, err := archiveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	header := append([]byte(archiveMagic), salt...)
+	out := append(header, nonce...)
+	return gcm.Seal(out, nonce, plain.Bytes(), header), nil
+}
+
+func openArchive(data []byte, passphrase string) (*sessionArchive, error) {
+	headerSize := len(archiveMagic) + archiveSaltSize
+	if len(data) < headerSize || string(data[:len(archiveMagic)]) != archiveMagic {
+		return nil, fmt.Errorf("invalid archive: unknown format")
+	}
+	header := data[:headerSize]
+	salt := data[len(archiveMagic):headerSize]
+
+	key, err := archiveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < headerSize+gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid archive: truncated")
+	}
+	nonce := data[headerSize : headerSize+gcm.NonceSize()]
+	plain, err := gcm.Open(nil, nonce, data[headerSize+gcm.NonceSize():], header)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive: wrong passphrase or corrupted data")
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(plain))
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	var archive sessionArchive
+	if err := json.NewDecoder(io.LimitReader(zr, maxArchiveSize)).Decode(&archive); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("invalid archive: too large or truncated")
+		}
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+	if archive.Version != archiveVersion {
+		return nil, fmt.Errorf("invalid archive: unsupported version %d", archive.Version)
+	}
+	return &archive, nil
+}
@@ -0,0 +1,60 @@
+package wameow
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSealOpenArchive(t *testing.T) {
+	archive := &sessionArchive{
+		Version:    archiveVersion,
+		ExportedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Source:     "instance-a",
+		Detached:   true,
+		Session:    archivedSession{Name: "vendas", JID: "5511999999999:1@s.whatsapp.net", TokenHash: "abc"},
+	}
+	const passphrase = "correct horse battery"
+
+	data, err := sealArchive(archive, passphrase)
+	if err != nil {
+		t.Fatalf("sealArchive: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		data       []byte
+		passphrase string
+		wantErr    string
+	}{
+		{name: "round trip", data: data, passphrase: passphrase},
+		{name: "wrong passphrase", data: data, passphrase: "wrong horse battery", wantErr: "wrong passphrase"},
+		{name: "unknown format", data: []byte("not an archive at all"), passphrase: passphrase, wantErr: "unknown format"},
+		{name: "truncated", data: data[:len(archiveMagic)+archiveSaltSize+4], passphrase: passphrase, wantErr: "truncated"},
+		{name: "tampered", data: flipLastByte(data), passphrase: passphrase, wantErr: "corrupted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := openArchive(tt.data, tt.passphrase)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("openArchive error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("openArchive: %v", err)
+			}
+			if !reflect.DeepEqual(got.Session, archive.Session) || got.Source != archive.Source || !got.Detached || !got.ExportedAt.Equal(archive.ExportedAt) {
+				t.Fatalf("openArchive = %+v, want %+v", got, archive)
+			}
+		})
+	}
+}
+
+func flipLastByte(data []byte) []byte {
+	out := append([]byte(nil), data...)
+	out[len(out)-1] ^= 0xff
+	return out
+}
@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Escopos dos buckets: por credencial autenticada, por sessao e por classe de rota (tambem por sessao)
+const (
+	ScopeToken        = "token"
+	ScopeSession      = "session"
+	ClassSend         = "send"          // envio de mensagens e status
+	ClassContactCheck = "contact_check" // verificacao de numeros no WhatsApp
+	ClassGroupWrite   = "group_write"   // criacao e alteracao de grupos e comunidades
+)
+
+// Limit taxa sustentada (tokens por segundo) e rajada de um token bucket. Rate 0 = sem limite
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Enabled indica se o limite esta configurado
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+// ParseLimit le limites no formato N/unidade[:rajada], ex.: "10/s", "300/m:20", "1000/h".
+// Sem rajada, ela e igual a N. Vazio ou "0" desativa
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return Limit{}, nil
+	}
+
+	spec, burstStr, hasBurst := strings.Cut(s, ":")
+	countStr, unit, ok := strings.Cut(spec, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: expected N/unit[:burst]", s)
+	}
+	count, err := strconv.Atoi(countStr)
+	if err != nil || count < 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: bad count", s)
+	}
+	var per time.Duration
+	switch unit {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		return Limit{}, fmt.Errorf("invalid rate limit %q: unit must be s, m or h", s)
+	}
+	if count == 0 {
+		return Limit{}, nil
+	}
+
+	burst := count
+	if hasBurst {
+		burst, err = strconv.Atoi(burstStr)
+		if err != nil || burst < 1 {
+			return Limit{}, fmt.Errorf("invalid rate limit %q: bad burst", s)
+		}
+	}
+	return Limit{Rate: float64(count) / per.Seconds(), Burst: burst}, nil
+}
+
+// Decision resultado da tentativa de consumir um token
+type Decision struct {
+	Allowed    bool
+	RetryAfter time.Duration // espera ate o proximo token (se negado)
+}
+
+// RetryAfterSeconds valor do header Retry-After (minimo 1)
+func (d Decision) RetryAfterSeconds() int {
+	return int(math.Max(1, math.Ceil(d.RetryAfter.Seconds())))
+}
+
+// Store guarda os buckets (memoria local ou Redis compartilhado entre replicas)
+type Store interface {
+	Allow(ctx context.Context, key string, limit Limit) (Decision, error)
+}
+
+// Limiter aplica os limites configurados por escopo
+type Limiter struct {
+	store  Store
+	limits map[string]Limit
+}
+
+// New cria um Limiter. Escopos sem limite configurado nao sao limitados
+func New(store Store, limits map[string]Limit) *Limiter {
+	return &Limiter{store: store, limits: limits}
+}
+
+// Allow consome um token do bucket escopo/chave
+func (l *Limiter) Allow(ctx context.Context, scope, key string) (Decision, error) {
+	if l == nil {
+		return Decision{Allowed: true}, nil
+	}
+	limit, ok := l.limits[scope]
+	if !ok || !limit.Enabled() {
+		return Decision{Allowed: true}, nil
+	}
+	return l.store.Allow(ctx, scope+":"+key, limit)
+}
+
+// Close libera o armazenamento (conexao com o Redis); nada a fazer em memoria
+func (l *Limiter) Close() error {
+	if l == nil {
+		return nil
+	}
+	if c, ok := l.store.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// retryAfter tempo ate o bucket ter um token inteiro
+func retryAfter(tokens float64, limit Limit) time.Duration {
+	return time.Duration((1 - tokens) / limit.Rate * float64(time.Second))
+}
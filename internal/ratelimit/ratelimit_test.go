@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Limit
+		wantErr bool
+	}{
+		{in: "", want: Limit{}},
+		{in: "0", want: Limit{}},
+		{in: "0/s", want: Limit{}},
+		{in: "10/s", want: Limit{Rate: 10, Burst: 10}},
+		{in: " 300/m:20 ", want: Limit{Rate: 5, Burst: 20}},
+		{in: "3600/h", want: Limit{Rate: 1, Burst: 3600}},
+		{in: "10", wantErr: true},
+		{in: "x/s", wantErr: true},
+		{in: "-1/s", wantErr: true},
+		{in: "10/d", wantErr: true},
+		{in: "10/s:0", wantErr: true},
+		{in: "10/s:x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLimit(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseLimit(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("ParseLimit(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreAllow(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       Limit
+		requests    int
+		wantAllowed int
+	}{
+		{name: "within burst", limit: Limit{Rate: 1, Burst: 3}, requests: 3, wantAllowed: 3},
+		{name: "burst exhausted", limit: Limit{Rate: 1, Burst: 3}, requests: 5, wantAllowed: 3},
+		{name: "single token", limit: Limit{Rate: 0.5, Burst: 1}, requests: 2, wantAllowed: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryStore()
+			allowed := 0
+			var last Decision
+			for i := 0; i < tt.requests; i++ {
+				d, err := store.Allow(context.Background(), "k", tt.limit)
+				if err != nil {
+					t.Fatalf("Allow: %v", err)
+				}
+				if d.Allowed {
+					allowed++
+				}
+				last = d
+			}
+			if allowed != tt.wantAllowed {
+				t.Fatalf("allowed = %d, want %d", allowed, tt.wantAllowed)
+			}
+			if tt.requests > tt.wantAllowed {
+				// Sem tokens, espera aproximadamente 1/Rate pelo proximo
+				want := time.Duration(float64(time.Second) / tt.limit.Rate)
+				if last.RetryAfter <= 0 || last.RetryAfter > want {
+					t.Fatalf("RetryAfter = %v, want (0, %v]", last.RetryAfter, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreKeysAreIndependent(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Rate: 1, Burst: 1}
+	for _, key := range []string{"a", "b"} {
+		if d, _ := store.Allow(context.Background(), key, limit); !d.Allowed {
+			t.Fatalf("first request for %q denied", key)
+		}
+	}
+	if d, _ := store.Allow(context.Background(), "a", limit); d.Allowed {
+		t.Fatal("second request for \"a\" allowed")
+	}
+}
+
+func TestLimiterUnconfiguredScope(t *testing.T) {
+	l := New(NewMemoryStore(), map[string]Limit{ScopeToken: {Rate: 1, Burst: 1}})
+	for i := 0; i < 3; i++ {
+		if d, _ := l.Allow(context.Background(), ScopeSession, "s"); !d.Allowed {
+			t.Fatal("unconfigured scope was limited")
+		}
+	}
+	var nilLimiter *Limiter
+	if d, _ := nilLimiter.Allow(context.Background(), ScopeToken, "t"); !d.Allowed {
+		t.Fatal("nil limiter denied request")
+	}
+	if err := nilLimiter.Close(); err != nil {
+		t.Fatalf("nil limiter Close: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKeyPrefix prefixo das chaves dos buckets no Redis
+const redisKeyPrefix = "fiozap:ratelimit:"
+
+// tokenBucketScript recarrega e consome o bucket de forma atomica, usando o relogio do Redis
+// para todas as replicas concordarem. Retorna {permitido, segundos ate o proximo token}
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) + tonumber(t[2]) / 1000000
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = (1 - tokens) / rate
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+return {allowed, tostring(retry)}
+`)
+
+// RedisStore buckets no Redis, compartilhados entre replicas
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore conecta ao Redis (REDIS_URL) e valida a conexao
+func NewRedisStore(ctx context.Context, url string) (*RedisStore, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	return &RedisStore{client: client}, nil
+}
+
+func (s *RedisStore) Allow(ctx context.Context, key string, limit Limit) (Decision, error) {
+	res, err := tokenBucketScript.Run(ctx, s.client, []string{redisKeyPrefix + key}, limit.Rate, limit.Burst).Slice()
+	if err != nil {
+		return Decision{}, err
+	}
+	if len(res) != 2 {
+		return Decision{}, fmt.Errorf("unexpected rate limit script result: %v", res)
+	}
+
+	allowed, _ := res[0].(int64)
+	retryStr, _ := res[1].(string)
+	retry, err := strconv.ParseFloat(retryStr, 64)
+	if err != nil {
+		return Decision{}, fmt.Errorf("unexpected rate limit retry value %q", retryStr)
+	}
+	return Decision{Allowed: allowed == 1, RetryAfter: time.Duration(retry * float64(time.Second))}, nil
+}
+
+// Close encerra a conexao com o Redis
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
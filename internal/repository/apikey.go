@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+)
+
+// APIKeyRepository define operacoes de persistencia de API keys
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *APIKeyModel) error
+	GetByHash(ctx context.Context, keyHash string) (*APIKeyModel, error)
+	List(ctx context.Context) ([]*APIKeyModel, error)
+	Delete(ctx context.Context, id string) (bool, error)
+	TouchLastUsed(ctx context.Context, id string) error
+}
+
+// apiKeyRepository implementa APIKeyRepository usando PostgreSQL
+type apiKeyRepository struct {
+	db *sql.DB
+}
+
+// NewAPIKeyRepository cria um novo APIKeyRepository
+func NewAPIKeyRepository(db *sql.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, key *APIKeyModel) error {
+	scopes, err := json.Marshal(key.Scopes)
+	if err != nil {
+		return err
+	}
+	sessions, err := json.Marshal(nonNil(key.Sessions))
+	if err != nil {
+		return err
+	}
+	ips, err := json.Marshal(nonNil(key.AllowedIPs))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO "api_keys" ("id", "name", "prefix", "keyHash", "scopes", "sessions", "allowedIps", "expiresAt")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, key.ID, key.Name, key.Prefix, key.KeyHash, scopes, sessions, ips, key.ExpiresAt)
+	return err
+}
+
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*APIKeyModel, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT "id", "name", "prefix", "keyHash", "scopes", "sessions", "allowedIps", "expiresAt", "lastUsedAt", "createdAt"
+		FROM "api_keys" WHERE "keyHash" = $1
+	`, keyHash)
+
+	key, err := scanAPIKey(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return key, err
+}
+
+func (r *apiKeyRepository) List(ctx context.Context) ([]*APIKeyModel, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT "id", "name", "prefix", "keyHash", "scopes", "sessions", "allowedIps", "expiresAt", "lastUsedAt", "createdAt"
+		FROM "api_keys"
+		ORDER BY "createdAt" ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var keys []*APIKeyModel
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// Delete remove a chave; retorna falso se ela nao existia
+func (r *apiKeyRepository) Delete(ctx context.Context, id string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM "api_keys" WHERE "id" = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *apiKeyRepository) TouchLastUsed(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE "api_keys" SET "lastUsedAt" = CURRENT_TIMESTAMP WHERE "id" = $1`, id)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIKey(row rowScanner) (*APIKeyModel, error) {
+	key := &APIKeyModel{}
+	var scopes, sessions, ips []byte
+	if err := row.Scan(
+		&key.ID, &key.Name, &key.Prefix, &key.KeyHash,
+		&scopes, &sessions, &ips,
+		&key.ExpiresAt, &key.LastUsedAt, &key.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(scopes, &key.Scopes); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(sessions, &key.Sessions); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(ips, &key.AllowedIPs); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// nonNil garante que listas vazias sejam gravadas como [] e nao null
+func nonNil(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
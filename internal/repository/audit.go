@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// AuditRepository define operacoes da trilha de auditoria (somente insercao e consulta)
+type AuditRepository interface {
+	Create(ctx context.Context, entry *AuditModel) error
+	List(ctx context.Context, filter AuditFilter) ([]*AuditModel, int, error)
+}
+
+// auditRepository implementa AuditRepository usando PostgreSQL
+type auditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository cria um novo AuditRepository
+func NewAuditRepository(db *sql.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Create(ctx context.Context, entry *AuditModel) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO "audit_log" ("actorType", "actorId", "ip", "requestId", "method", "route", "path", "session", "status", "outcome")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, entry.ActorType, entry.ActorID, entry.IP, entry.RequestID, entry.Method, entry.Route, entry.Path, entry.Session, entry.Status, entry.Outcome)
+	return err
+}
+
+// List retorna os registros mais recentes primeiro e o total sem paginacao
+func (r *auditRepository) List(ctx context.Context, filter AuditFilter) ([]*AuditModel, int, error) {
+	var where []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if filter.ActorType != "" {
+		where = append(where, `"actorType" = `+arg(filter.ActorType))
+	}
+	if filter.ActorID != "" {
+		where = append(where, `"actorId" = `+arg(filter.ActorID))
+	}
+	if filter.Session != "" {
+		where = append(where, `"session" = `+arg(filter.Session))
+	}
+	if filter.Outcome != "" {
+		where = append(where, `"outcome" = `+arg(filter.Outcome))
+	}
+	if !filter.Since.IsZero() {
+		where = append(where, `"createdAt" >= `+arg(filter.Since))
+	}
+	if !filter.Until.IsZero() {
+		where = append(where, `"createdAt" < `+arg(filter.Until))
+	}
+
+	cond := ""
+	if len(where) > 0 {
+		cond = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM "audit_log"`+cond, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `SELECT "id", "actorType", "actorId", "ip", "requestId", "method", "route", "path", "session", "status", "outcome", "createdAt"
+		FROM "audit_log"` + cond + ` ORDER BY "createdAt" DESC, "id" DESC`
+	if filter.Limit > 0 {
+		query += ` LIMIT ` + arg(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query += ` OFFSET ` + arg(filter.Offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []*AuditModel
+	for rows.Next() {
+		e := &AuditModel{}
+		if err := rows.Scan(
+			&e.ID, &e.ActorType, &e.ActorID, &e.IP, &e.RequestID, &e.Method,
+			&e.Route, &e.Path, &e.Session, &e.Status, &e.Outcome, &e.CreatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, total, rows.Err()
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// LeaseRepository define operacoes de posse de sessoes entre instancias.
+// Expiracao calculada com o relogio do banco para nao depender do relogio das instancias
+type LeaseRepository interface {
+	Acquire(ctx context.Context, sessionID, ownerID, ownerAddr string, ttl time.Duration) (bool, error)
+	Renew(ctx context.Context, ownerID, ownerAddr string, ttl time.Duration) ([]string, error)
+	Release(ctx context.Context, sessionID, ownerID string) error
+	ReleaseAll(ctx context.Context, ownerID string) error
+	Get(ctx context.Context, sessionID string) (*LeaseModel, error)
+	ListActive(ctx context.Context) ([]*LeaseModel, error)
+	ListOrphaned(ctx context.Context, statuses []string) ([]string, error)
+}
+
+// leaseRepository implementa LeaseRepository usando PostgreSQL
+type leaseRepository struct {
+	db *sql.DB
+}
+
+// NewLeaseRepository cria um novo LeaseRepository
+func NewLeaseRepository(db *sql.DB) LeaseRepository {
+	return &leaseRepository{db: db}
+}
+
+// Acquire toma a posse se a sessao estiver livre, expirada ou ja for do mesmo dono
+func (r *leaseRepository) Acquire(ctx context.Context, sessionID, ownerID, ownerAddr string, ttl time.Duration) (bool, error) {
+	var owner string
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO "session_leases" ("sessionId", "ownerId", "ownerAddr", "expiresAt")
+		VALUES ($1, $2, $3, NOW() + make_interval(secs => $4))
+		ON CONFLICT ("sessionId") DO UPDATE SET
+			"ownerId" = EXCLUDED."ownerId",
+			"ownerAddr" = EXCLUDED."ownerAddr",
+			"acquiredAt" = CASE WHEN "session_leases"."ownerId" = EXCLUDED."ownerId" THEN "session_leases"."acquiredAt" ELSE CURRENT_TIMESTAMP END,
+			"expiresAt" = EXCLUDED."expiresAt"
+		WHERE "session_leases"."ownerId" = EXCLUDED."ownerId" OR "session_leases"."expiresAt" < NOW()
+		RETURNING "ownerId"
+	`, sessionID, ownerID, NullString(ownerAddr), ttl.Seconds()).Scan(&owner)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Renew estende todas as posses do dono e retorna os IDs das sessoes que continuam dele
+func (r *leaseRepository) Renew(ctx context.Context, ownerID, ownerAddr string, ttl time.Duration) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE "session_leases" SET "ownerAddr" = $2, "expiresAt" = NOW() + make_interval(secs => $3)
+		WHERE "ownerId" = $1
+		RETURNING "sessionId"
+	`, ownerID, NullString(ownerAddr), ttl.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *leaseRepository) Release(ctx context.Context, sessionID, ownerID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM "session_leases" WHERE "sessionId" = $1 AND "ownerId" = $2`, sessionID, ownerID)
+	return err
+}
+
+func (r *leaseRepository) ReleaseAll(ctx context.Context, ownerID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM "session_leases" WHERE "ownerId" = $1`, ownerID)
+	return err
+}
+
+// Get retorna a posse da sessao (nil se nunca foi tomada)
+func (r *leaseRepository) Get(ctx context.Context, sessionID string) (*LeaseModel, error) {
+	lease := &LeaseModel{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT "sessionId", "ownerId", "ownerAddr", "acquiredAt", "expiresAt", "expiresAt" < NOW()
+		FROM "session_leases" WHERE "sessionId" = $1
+	`, sessionID).Scan(&lease.SessionID, &lease.OwnerID, &lease.OwnerAddr, &lease.AcquiredAt, &lease.ExpiresAt, &lease.Expired)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return lease, err
+}
+
+// ListActive lista as posses ainda validas
+func (r *leaseRepository) ListActive(ctx context.Context) ([]*LeaseModel, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT "sessionId", "ownerId", "ownerAddr", "acquiredAt", "expiresAt", FALSE
+		FROM "session_leases" WHERE "expiresAt" >= NOW()
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var leases []*LeaseModel
+	for rows.Next() {
+		l := &LeaseModel{}
+		if err := rows.Scan(&l.SessionID, &l.OwnerID, &l.OwnerAddr, &l.AcquiredAt, &l.ExpiresAt, &l.Expired); err != nil {
+			return nil, err
+		}
+		leases = append(leases, l)
+	}
+	return leases, rows.Err()
+}
+
+// ListOrphaned retorna nomes de sessoes pareadas, em um dos estados informados ou desligadas
+// pelo shutdown de outra instancia, sem dono valido
+func (r *leaseRepository) ListOrphaned(ctx context.Context, statuses []string) ([]string, error) {
+	list, err := json.Marshal(nonNil(statuses))
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT s."name" FROM "sessions" s
+		LEFT JOIN "session_leases" l ON l."sessionId" = s."id"
+		WHERE s."jid" IS NOT NULL
+			AND (s."status" IN (SELECT jsonb_array_elements_text($1::jsonb)) OR s."reconnectOnBoot")
+			AND (l."sessionId" IS NULL OR l."expiresAt" < NOW())
+		ORDER BY s."createdAt" ASC
+	`, string(list))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
@@ -7,15 +7,146 @@ import (
 
 // SessionModel representa uma sessao no banco de dados
 type SessionModel struct {
-	ID        string
-	Name      string
-	Token     string
-	JID       sql.NullString
-	Phone     sql.NullString
-	PushName  sql.NullString
-	Connected bool
+	ID                     string
+	Name                   string
+	TenantID               sql.NullString
+	Proxy                  sql.NullString
+	Metadata               map[string]string
+	Tags                   []string
+	DeviceName             sql.NullString
+	DevicePlatform         sql.NullString
+	IdleTimeout            int // segundos sem uso da API ate desconectar (0 = nunca)
+	TokenHash              string
+	TokenExpiresAt         sql.NullTime
+	PreviousTokenHash      sql.NullString
+	PreviousTokenExpiresAt sql.NullTime
+	JID                    sql.NullString
+	Phone                  sql.NullString
+	PushName               sql.NullString
+	Connected              bool
+	ReconnectOnBoot        bool // desligada pelo graceful shutdown
+	Status                 string
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
+}
+
+// LeaseModel posse de uma sessao por uma instancia
+type LeaseModel struct {
+	SessionID  string
+	OwnerID    string
+	OwnerAddr  sql.NullString
+	AcquiredAt time.Time
+	ExpiresAt  time.Time
+	Expired    bool
+}
+
+// SessionFilter filtros, ordenacao e paginacao da busca de sessoes (campos vazios nao filtram)
+type SessionFilter struct {
+	Status     string
+	Tag        string
+	Phone      string // prefixo
+	NamePrefix string
+	TenantID   string
+	Names      []string // allowlist de nomes (API key restrita)
+	SortBy     string   // name, createdAt, updatedAt, status ou phone
+	Desc       bool
+	Limit      int
+	Offset     int
+}
+
+// SessionStatusModel representa uma transicao de estado da sessao
+type SessionStatusModel struct {
+	ID         int64
+	SessionID  string
+	FromStatus sql.NullString
+	ToStatus   string
+	Reason     sql.NullString
+	CreatedAt  time.Time
+}
+
+// PollModel representa uma enquete enviada ou recebida
+type PollModel struct {
+	SessionID       string
+	MessageID       string
+	ChatJID         string
+	SenderJID       sql.NullString
+	Question        string
+	Options         []string
+	SelectableCount int
+	CreatedAt       time.Time
+}
+
+// PollVoteModel representa o voto atual de um participante
+type PollVoteModel struct {
+	SessionID string
+	MessageID string
+	VoterJID  string
+	Options   []string
+	VotedAt   time.Time
+}
+
+// StatusModel representa um status (story) recebido
+type StatusModel struct {
+	SessionID string
+	MessageID string
+	SenderJID string
+	Type      string
+	Body      sql.NullString
+	MimeType  sql.NullString
+	Timestamp time.Time
 	CreatedAt time.Time
-	UpdatedAt time.Time
+}
+
+// TenantModel representa um cliente com sessoes isoladas e cotas (0 = ilimitado)
+type TenantModel struct {
+	ID                string
+	Name              string
+	TokenHash         string
+	MaxSessions       int
+	MaxMessagesPerDay int
+	CreatedAt         time.Time
+}
+
+// APIKeyModel representa uma API key com escopos
+type APIKeyModel struct {
+	ID         string
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	Sessions   []string // vazio = todas as sessoes
+	AllowedIPs []string // IPs ou CIDRs; vazio = qualquer origem
+	ExpiresAt  sql.NullTime
+	LastUsedAt sql.NullTime
+	CreatedAt  time.Time
+}
+
+// AuditModel registro da trilha de auditoria
+type AuditModel struct {
+	ID        int64
+	ActorType string         // global, tenant, apikey, session ou anonymous
+	ActorID   sql.NullString // id do tenant ou da API key, nome da sessao
+	IP        sql.NullString
+	RequestID sql.NullString
+	Method    string
+	Route     string // padrao da rota (ex.: /sessions/{name}/logout)
+	Path      string
+	Session   sql.NullString // sessao alvo
+	Status    int
+	Outcome   string // success, failure ou denied
+	CreatedAt time.Time
+}
+
+// AuditFilter filtros e paginacao da consulta de auditoria (campos vazios nao filtram)
+type AuditFilter struct {
+	ActorType string
+	ActorID   string
+	Session   string
+	Outcome   string
+	Since     time.Time
+	Until     time.Time
+	Limit     int
+	Offset    int
 }
 
 // GetJID retorna JID como string (vazio se null)
@@ -42,6 +173,14 @@ func (s *SessionModel) GetPushName() string {
 	return ""
 }
 
+// NullTime converte time.Time para sql.NullTime (zero vira null)
+func NullTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t, Valid: true}
+}
+
 // NullString converte string para sql.NullString
 func NullString(s string) sql.NullString {
 	if s == "" {
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+)
+
+// PollRepository define operacoes de persistencia de enquetes e votos
+type PollRepository interface {
+	Create(ctx context.Context, poll *PollModel) error
+	Get(ctx context.Context, sessionID, messageID string) (*PollModel, error)
+	UpsertVote(ctx context.Context, vote *PollVoteModel) error
+	ListVotes(ctx context.Context, sessionID, messageID string) ([]*PollVoteModel, error)
+}
+
+// pollRepository implementa PollRepository usando PostgreSQL
+type pollRepository struct {
+	db *sql.DB
+}
+
+// NewPollRepository cria um novo PollRepository
+func NewPollRepository(db *sql.DB) PollRepository {
+	return &pollRepository{db: db}
+}
+
+func (r *pollRepository) Create(ctx context.Context, poll *PollModel) error {
+	options, err := json.Marshal(poll.Options)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO "polls" ("sessionId", "messageId", "chatJid", "senderJid", "question", "options", "selectableCount")
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT ("sessionId", "messageId") DO NOTHING
+	`, poll.SessionID, poll.MessageID, poll.ChatJID, poll.SenderJID, poll.Question, options, poll.SelectableCount)
+	return err
+}
+
+func (r *pollRepository) Get(ctx context.Context, sessionID, messageID string) (*PollModel, error) {
+	poll := &PollModel{}
+	var options []byte
+	err := r.db.QueryRowContext(ctx, `
+		SELECT "sessionId", "messageId", "chatJid", "senderJid", "question", "options", "selectableCount", "createdAt"
+		FROM "polls" WHERE "sessionId" = $1 AND "messageId" = $2
+	`, sessionID, messageID).Scan(
+		&poll.SessionID, &poll.MessageID, &poll.ChatJID, &poll.SenderJID,
+		&poll.Question, &options, &poll.SelectableCount, &poll.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(options, &poll.Options); err != nil {
+		return nil, err
+	}
+	return poll, nil
+}
+
+func (r *pollRepository) UpsertVote(ctx context.Context, vote *PollVoteModel) error {
+	options, err := json.Marshal(vote.Options)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO "poll_votes" ("sessionId", "messageId", "voterJid", "options", "votedAt")
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT ("sessionId", "messageId", "voterJid") DO UPDATE SET
+			"options" = EXCLUDED."options",
+			"votedAt" = EXCLUDED."votedAt"
+		WHERE "poll_votes"."votedAt" <= EXCLUDED."votedAt"
+	`, vote.SessionID, vote.MessageID, vote.VoterJID, options, vote.VotedAt)
+	return err
+}
+
+func (r *pollRepository) ListVotes(ctx context.Context, sessionID, messageID string) ([]*PollVoteModel, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT "sessionId", "messageId", "voterJid", "options", "votedAt"
+		FROM "poll_votes"
+		WHERE "sessionId" = $1 AND "messageId" = $2
+		ORDER BY "votedAt" ASC
+	`, sessionID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var votes []*PollVoteModel
+	for rows.Next() {
+		v := &PollVoteModel{}
+		var options []byte
+		if err := rows.Scan(&v.SessionID, &v.MessageID, &v.VoterJID, &options, &v.VotedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(options, &v.Options); err != nil {
+			return nil, err
+		}
+		votes = append(votes, v)
+	}
+	return votes, rows.Err()
+}
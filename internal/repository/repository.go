@@ -4,12 +4,26 @@ import "database/sql"
 
 // Repositories agrupa todos os repositories da aplicacao
 type Repositories struct {
-	Session SessionRepository
+	Session  SessionRepository
+	Poll     PollRepository
+	Status   StatusRepository
+	APIKey   APIKeyRepository
+	Tenant   TenantRepository
+	Lease    LeaseRepository
+	Transfer TransferRepository
+	Audit    AuditRepository
 }
 
 // New cria todos os repositories
 func New(db *sql.DB) *Repositories {
 	return &Repositories{
-		Session: NewSessionRepository(db),
+		Session:  NewSessionRepository(db),
+		Poll:     NewPollRepository(db),
+		Status:   NewStatusRepository(db),
+		APIKey:   NewAPIKeyRepository(db),
+		Tenant:   NewTenantRepository(db),
+		Lease:    NewLeaseRepository(db),
+		Transfer: NewTransferRepository(db),
+		Audit:    NewAuditRepository(db),
 	}
 }
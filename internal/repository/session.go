@@ -3,17 +3,28 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
 )
 
 // SessionRepository define operacoes de persistencia de sessoes
 type SessionRepository interface {
 	Create(ctx context.Context, session *SessionModel) error
 	GetByName(ctx context.Context, name string) (*SessionModel, error)
-	GetByToken(ctx context.Context, token string) (*SessionModel, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*SessionModel, error)
 	List(ctx context.Context) ([]*SessionModel, error)
 	Update(ctx context.Context, session *SessionModel) error
 	Delete(ctx context.Context, name string) error
 	UpdateConnection(ctx context.Context, name string, connected bool, jid, phone, pushName string) error
+	UpdateStatus(ctx context.Context, sessionID, from, to, reason string) error
+	UpdateToken(ctx context.Context, session *SessionModel) error
+	UpdateProxy(ctx context.Context, sessionID, proxy string) error
+	UpdateIdleTimeout(ctx context.Context, sessionID string, seconds int) error
+	MarkShutdown(ctx context.Context, sessionID string) error
+	UpdateLabels(ctx context.Context, session *SessionModel) error
+	Search(ctx context.Context, filter SessionFilter) ([]*SessionModel, int, error)
+	ListStatusHistory(ctx context.Context, sessionID string, limit int) ([]*SessionStatusModel, error)
 }
 
 // sessionRepository implementa SessionRepository usando PostgreSQL
@@ -26,40 +37,40 @@ func NewSessionRepository(db *sql.DB) SessionRepository {
 	return &sessionRepository{db: db}
 }
 
+// sessionColumns colunas lidas por scanSession, na mesma ordem
+const sessionColumns = `"id", "name", "tenantId", "proxy", "metadata", "tags", "deviceName", "devicePlatform", "idleTimeout", "tokenHash", "tokenExpiresAt", "previousTokenHash", "previousTokenExpiresAt", "jid", "phone", "pushName", "connected", "reconnectOnBoot", "status", "createdAt", "updatedAt"`
+
+// sessionSortColumns colunas aceitas na ordenacao da busca
+var sessionSortColumns = map[string]string{
+	"name":      `"name"`,
+	"createdAt": `"createdAt"`,
+	"updatedAt": `"updatedAt"`,
+	"status":    `"status"`,
+	"phone":     `"phone"`,
+}
+
 func (r *sessionRepository) Create(ctx context.Context, session *SessionModel) error {
-	_, err := r.db.ExecContext(ctx, `
-		INSERT INTO "sessions" ("id", "name", "token", "jid", "phone", "pushName", "connected")
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-	`, session.ID, session.Name, session.Token, session.JID, session.Phone, session.PushName, session.Connected)
+	metadata, tags, err := marshalSessionLabels(session)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO "sessions" ("id", "name", "tenantId", "proxy", "metadata", "tags", "deviceName", "devicePlatform", "idleTimeout", "tokenHash", "tokenExpiresAt", "jid", "phone", "pushName", "connected", "status")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	`, session.ID, session.Name, session.TenantID, session.Proxy, metadata, tags, session.DeviceName, session.DevicePlatform, session.IdleTimeout, session.TokenHash, session.TokenExpiresAt, session.JID, session.Phone, session.PushName, session.Connected, session.Status)
 	return err
 }
 
 func (r *sessionRepository) GetByName(ctx context.Context, name string) (*SessionModel, error) {
-	session := &SessionModel{}
-	err := r.db.QueryRowContext(ctx, `
-		SELECT "id", "name", "token", "jid", "phone", "pushName", "connected", "createdAt", "updatedAt"
-		FROM "sessions" WHERE "name" = $1
-	`, name).Scan(
-		&session.ID, &session.Name, &session.Token, &session.JID,
-		&session.Phone, &session.PushName, &session.Connected,
-		&session.CreatedAt, &session.UpdatedAt,
-	)
+	session, err := scanSession(r.db.QueryRowContext(ctx, `SELECT `+sessionColumns+` FROM "sessions" WHERE "name" = $1`, name))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	return session, err
 }
 
-func (r *sessionRepository) GetByToken(ctx context.Context, token string) (*SessionModel, error) {
-	session := &SessionModel{}
-	err := r.db.QueryRowContext(ctx, `
-		SELECT "id", "name", "token", "jid", "phone", "pushName", "connected", "createdAt", "updatedAt"
-		FROM "sessions" WHERE "token" = $1
-	`, token).Scan(
-		&session.ID, &session.Name, &session.Token, &session.JID,
-		&session.Phone, &session.PushName, &session.Connected,
-		&session.CreatedAt, &session.UpdatedAt,
-	)
+func (r *sessionRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*SessionModel, error) {
+	session, err := scanSession(r.db.QueryRowContext(ctx, `SELECT `+sessionColumns+` FROM "sessions" WHERE "tokenHash" = $1`, tokenHash))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -67,11 +78,7 @@ func (r *sessionRepository) GetByToken(ctx context.Context, token string) (*Sess
 }
 
 func (r *sessionRepository) List(ctx context.Context) ([]*SessionModel, error) {
-	rows, err := r.db.QueryContext(ctx, `
-		SELECT "id", "name", "token", "jid", "phone", "pushName", "connected", "createdAt", "updatedAt"
-		FROM "sessions"
-		ORDER BY "createdAt" ASC
-	`)
+	rows, err := r.db.QueryContext(ctx, `SELECT `+sessionColumns+` FROM "sessions" ORDER BY "createdAt" ASC`)
 	if err != nil {
 		return nil, err
 	}
@@ -79,12 +86,8 @@ func (r *sessionRepository) List(ctx context.Context) ([]*SessionModel, error) {
 
 	var sessions []*SessionModel
 	for rows.Next() {
-		s := &SessionModel{}
-		if err := rows.Scan(
-			&s.ID, &s.Name, &s.Token, &s.JID,
-			&s.Phone, &s.PushName, &s.Connected,
-			&s.CreatedAt, &s.UpdatedAt,
-		); err != nil {
+		s, err := scanSession(rows)
+		if err != nil {
 			return nil, err
 		}
 		sessions = append(sessions, s)
@@ -92,6 +95,82 @@ func (r *sessionRepository) List(ctx context.Context) ([]*SessionModel, error) {
 	return sessions, rows.Err()
 }
 
+// Search busca sessoes com filtros, ordenacao e paginacao. Retorna tambem o total sem paginacao
+func (r *sessionRepository) Search(ctx context.Context, filter SessionFilter) ([]*SessionModel, int, error) {
+	var where []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if filter.Status != "" {
+		where = append(where, `"status" = `+arg(filter.Status))
+	}
+	if filter.Tag != "" {
+		where = append(where, `"tags" @> jsonb_build_array(`+arg(filter.Tag)+`::text)`)
+	}
+	if filter.Phone != "" {
+		where = append(where, `"phone" LIKE `+arg(escapeLike(filter.Phone)+"%"))
+	}
+	if filter.NamePrefix != "" {
+		where = append(where, `"name" LIKE `+arg(escapeLike(filter.NamePrefix)+"%"))
+	}
+	if filter.TenantID != "" {
+		where = append(where, `"tenantId" = `+arg(filter.TenantID))
+	}
+	if filter.Names != nil {
+		names, err := json.Marshal(filter.Names)
+		if err != nil {
+			return nil, 0, err
+		}
+		where = append(where, `"name" IN (SELECT jsonb_array_elements_text(`+arg(string(names))+`::jsonb))`)
+	}
+
+	cond := ""
+	if len(where) > 0 {
+		cond = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM "sessions"`+cond, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	sortCol, ok := sessionSortColumns[filter.SortBy]
+	if !ok {
+		sortCol = `"createdAt"`
+	}
+	order := "ASC"
+	if filter.Desc {
+		order = "DESC"
+	}
+	query := `SELECT ` + sessionColumns + ` FROM "sessions"` + cond +
+		` ORDER BY ` + sortCol + ` ` + order + ` NULLS LAST, "name" ASC`
+	if filter.Limit > 0 {
+		query += ` LIMIT ` + arg(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query += ` OFFSET ` + arg(filter.Offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var sessions []*SessionModel
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, total, rows.Err()
+}
+
 func (r *sessionRepository) Update(ctx context.Context, session *SessionModel) error {
 	_, err := r.db.ExecContext(ctx, `
 		UPDATE "sessions" SET 
@@ -99,6 +178,7 @@ func (r *sessionRepository) Update(ctx context.Context, session *SessionModel) e
 			"phone" = $2, 
 			"pushName" = $3, 
 			"connected" = $4,
+			"reconnectOnBoot" = FALSE,
 			"updatedAt" = CURRENT_TIMESTAMP
 		WHERE "name" = $5
 	`, session.JID, session.Phone, session.PushName, session.Connected, session.Name)
@@ -117,8 +197,150 @@ func (r *sessionRepository) UpdateConnection(ctx context.Context, name string, c
 			"phone" = $2, 
 			"pushName" = $3, 
 			"connected" = $4,
+			"reconnectOnBoot" = FALSE,
 			"updatedAt" = CURRENT_TIMESTAMP
 		WHERE "name" = $5
 	`, NullString(jid), NullString(phone), NullString(pushName), connected, name)
 	return err
 }
+
+// UpdateStatus grava o novo estado da sessao e registra a transicao no historico
+func (r *sessionRepository) UpdateStatus(ctx context.Context, sessionID, from, to, reason string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE "sessions" SET "status" = $1, "updatedAt" = CURRENT_TIMESTAMP WHERE "id" = $2
+	`, to, sessionID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO "session_status_history" ("sessionId", "fromStatus", "toStatus", "reason")
+		VALUES ($1, $2, $3, $4)
+	`, sessionID, NullString(from), to, NullString(reason)); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ListStatusHistory retorna as transicoes mais recentes primeiro
+func (r *sessionRepository) ListStatusHistory(ctx context.Context, sessionID string, limit int) ([]*SessionStatusModel, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT "id", "sessionId", "fromStatus", "toStatus", "reason", "createdAt"
+		FROM "session_status_history"
+		WHERE "sessionId" = $1
+		ORDER BY "createdAt" DESC, "id" DESC
+		LIMIT $2
+	`, sessionID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var history []*SessionStatusModel
+	for rows.Next() {
+		h := &SessionStatusModel{}
+		if err := rows.Scan(&h.ID, &h.SessionID, &h.FromStatus, &h.ToStatus, &h.Reason, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, h)
+	}
+	return history, rows.Err()
+}
+
+// UpdateToken grava o hash do token atual e do anterior (carencia de rotacao)
+func (r *sessionRepository) UpdateToken(ctx context.Context, session *SessionModel) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE "sessions" SET
+			"tokenHash" = $1,
+			"tokenExpiresAt" = $2,
+			"previousTokenHash" = $3,
+			"previousTokenExpiresAt" = $4,
+			"updatedAt" = CURRENT_TIMESTAMP
+		WHERE "id" = $5
+	`, session.TokenHash, session.TokenExpiresAt, session.PreviousTokenHash, session.PreviousTokenExpiresAt, session.ID)
+	return err
+}
+
+// UpdateProxy grava o proxy da sessao (vazio remove)
+func (r *sessionRepository) UpdateProxy(ctx context.Context, sessionID, proxy string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE "sessions" SET "proxy" = $1, "updatedAt" = CURRENT_TIMESTAMP WHERE "id" = $2
+	`, NullString(proxy), sessionID)
+	return err
+}
+
+// UpdateIdleTimeout grava a politica de inatividade da sessao (0 desativa)
+func (r *sessionRepository) UpdateIdleTimeout(ctx context.Context, sessionID string, seconds int) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE "sessions" SET "idleTimeout" = $1, "updatedAt" = CURRENT_TIMESTAMP WHERE "id" = $2
+	`, seconds, sessionID)
+	return err
+}
+
+// MarkShutdown marca a sessao como desconectada pelo shutdown, para reconectar no proximo boot
+func (r *sessionRepository) MarkShutdown(ctx context.Context, sessionID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE "sessions" SET "connected" = FALSE, "reconnectOnBoot" = TRUE, "updatedAt" = CURRENT_TIMESTAMP WHERE "id" = $1
+	`, sessionID)
+	return err
+}
+
+// UpdateLabels grava metadados e tags da sessao
+func (r *sessionRepository) UpdateLabels(ctx context.Context, session *SessionModel) error {
+	metadata, tags, err := marshalSessionLabels(session)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		UPDATE "sessions" SET "metadata" = $1, "tags" = $2, "updatedAt" = CURRENT_TIMESTAMP WHERE "id" = $3
+	`, metadata, tags, session.ID)
+	return err
+}
+
+func scanSession(row rowScanner) (*SessionModel, error) {
+	s := &SessionModel{}
+	var metadata, tags []byte
+	if err := row.Scan(
+		&s.ID, &s.Name, &s.TenantID, &s.Proxy, &metadata, &tags, &s.DeviceName, &s.DevicePlatform, &s.IdleTimeout, &s.TokenHash, &s.TokenExpiresAt,
+		&s.PreviousTokenHash, &s.PreviousTokenExpiresAt, &s.JID,
+		&s.Phone, &s.PushName, &s.Connected, &s.ReconnectOnBoot, &s.Status,
+		&s.CreatedAt, &s.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(metadata, &s.Metadata); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(tags, &s.Tags); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// marshalSessionLabels serializa metadados e tags (vazios viram {} e [])
+func marshalSessionLabels(session *SessionModel) (string, string, error) {
+	metadata := session.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	m, err := json.Marshal(metadata)
+	if err != nil {
+		return "", "", err
+	}
+	t, err := json.Marshal(nonNil(session.Tags))
+	if err != nil {
+		return "", "", err
+	}
+	return string(m), string(t), nil
+}
+
+// escapeLike escapa curingas do LIKE para busca por prefixo literal
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
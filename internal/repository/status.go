@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// StatusRepository define operacoes de persistencia de status recebidos
+type StatusRepository interface {
+	Create(ctx context.Context, status *StatusModel) error
+	List(ctx context.Context, sessionID, senderJID string, limit int) ([]*StatusModel, error)
+}
+
+// statusRepository implementa StatusRepository usando PostgreSQL
+type statusRepository struct {
+	db *sql.DB
+}
+
+// NewStatusRepository cria um novo StatusRepository
+func NewStatusRepository(db *sql.DB) StatusRepository {
+	return &statusRepository{db: db}
+}
+
+func (r *statusRepository) Create(ctx context.Context, status *StatusModel) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO "statuses" ("sessionId", "messageId", "senderJid", "type", "body", "mimeType", "timestamp")
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT ("sessionId", "messageId") DO NOTHING
+	`, status.SessionID, status.MessageID, status.SenderJID, status.Type, status.Body, status.MimeType, status.Timestamp)
+	return err
+}
+
+// List retorna os status mais recentes, opcionalmente filtrados pelo remetente
+func (r *statusRepository) List(ctx context.Context, sessionID, senderJID string, limit int) ([]*StatusModel, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT "sessionId", "messageId", "senderJid", "type", "body", "mimeType", "timestamp", "createdAt"
+		FROM "statuses"
+		WHERE "sessionId" = $1 AND ($2 = '' OR "senderJid" = $2)
+		ORDER BY "timestamp" DESC
+		LIMIT $3
+	`, sessionID, senderJID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var statuses []*StatusModel
+	for rows.Next() {
+		s := &StatusModel{}
+		if err := rows.Scan(
+			&s.SessionID, &s.MessageID, &s.SenderJID, &s.Type,
+			&s.Body, &s.MimeType, &s.Timestamp, &s.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, rows.Err()
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// TenantRepository define operacoes de persistencia de tenants e uso de cota
+type TenantRepository interface {
+	Create(ctx context.Context, tenant *TenantModel) error
+	Get(ctx context.Context, id string) (*TenantModel, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*TenantModel, error)
+	List(ctx context.Context) ([]*TenantModel, error)
+	UpdateQuotas(ctx context.Context, tenant *TenantModel) error
+	Delete(ctx context.Context, id string) (bool, error)
+	IncrementMessages(ctx context.Context, tenantID string, day time.Time, limit int) (bool, error)
+	ReleaseMessage(ctx context.Context, tenantID string, day time.Time) error
+	MessagesOn(ctx context.Context, tenantID string, day time.Time) (int, error)
+}
+
+// tenantRepository implementa TenantRepository usando PostgreSQL
+type tenantRepository struct {
+	db *sql.DB
+}
+
+// NewTenantRepository cria um novo TenantRepository
+func NewTenantRepository(db *sql.DB) TenantRepository {
+	return &tenantRepository{db: db}
+}
+
+func (r *tenantRepository) Create(ctx context.Context, tenant *TenantModel) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO "tenants" ("id", "name", "tokenHash", "maxSessions", "maxMessagesPerDay")
+		VALUES ($1, $2, $3, $4, $5)
+	`, tenant.ID, tenant.Name, tenant.TokenHash, tenant.MaxSessions, tenant.MaxMessagesPerDay)
+	return err
+}
+
+func (r *tenantRepository) Get(ctx context.Context, id string) (*TenantModel, error) {
+	return r.getBy(ctx, `"id"`, id)
+}
+
+func (r *tenantRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*TenantModel, error) {
+	return r.getBy(ctx, `"tokenHash"`, tokenHash)
+}
+
+func (r *tenantRepository) getBy(ctx context.Context, column, value string) (*TenantModel, error) {
+	t := &TenantModel{}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT "id", "name", "tokenHash", "maxSessions", "maxMessagesPerDay", "createdAt"
+		FROM "tenants" WHERE `+column+` = $1
+	`, value).Scan(&t.ID, &t.Name, &t.TokenHash, &t.MaxSessions, &t.MaxMessagesPerDay, &t.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return t, err
+}
+
+func (r *tenantRepository) List(ctx context.Context) ([]*TenantModel, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT "id", "name", "tokenHash", "maxSessions", "maxMessagesPerDay", "createdAt"
+		FROM "tenants"
+		ORDER BY "createdAt" ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var tenants []*TenantModel
+	for rows.Next() {
+		t := &TenantModel{}
+		if err := rows.Scan(&t.ID, &t.Name, &t.TokenHash, &t.MaxSessions, &t.MaxMessagesPerDay, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, t)
+	}
+	return tenants, rows.Err()
+}
+
+func (r *tenantRepository) UpdateQuotas(ctx context.Context, tenant *TenantModel) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE "tenants" SET "maxSessions" = $1, "maxMessagesPerDay" = $2 WHERE "id" = $3
+	`, tenant.MaxSessions, tenant.MaxMessagesPerDay, tenant.ID)
+	return err
+}
+
+// Delete remove o tenant; falha enquanto houver sessoes dele (FK RESTRICT)
+func (r *tenantRepository) Delete(ctx context.Context, id string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM "tenants" WHERE "id" = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// IncrementMessages conta uma mensagem no dia se ainda houver cota; limit <= 0 = ilimitado
+func (r *tenantRepository) IncrementMessages(ctx context.Context, tenantID string, day time.Time, limit int) (bool, error) {
+	if limit <= 0 {
+		limit = int(^uint32(0) >> 1)
+	}
+
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO "tenant_message_usage" ("tenantId", "day", "count")
+		VALUES ($1, $2, 1)
+		ON CONFLICT ("tenantId", "day") DO UPDATE SET "count" = "tenant_message_usage"."count" + 1
+		WHERE "tenant_message_usage"."count" < $3
+		RETURNING "count"
+	`, tenantID, day.Format("2006-01-02"), limit).Scan(&count)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ReleaseMessage devolve uma mensagem contada por IncrementMessages (envio que falhou)
+func (r *tenantRepository) ReleaseMessage(ctx context.Context, tenantID string, day time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE "tenant_message_usage" SET "count" = "count" - 1
+		WHERE "tenantId" = $1 AND "day" = $2 AND "count" > 0
+	`, tenantID, day.Format("2006-01-02"))
+	return err
+}
+
+func (r *tenantRepository) MessagesOn(ctx context.Context, tenantID string, day time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT "count" FROM "tenant_message_usage" WHERE "tenantId" = $1 AND "day" = $2
+	`, tenantID, day.Format("2006-01-02")).Scan(&count)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return count, err
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+)
+
+// DeviceData linhas das tabelas do whatsmeow de um device, por tabela (JSON de cada linha)
+type DeviceData map[string][]json.RawMessage
+
+// deviceTables tabelas do sqlstore do whatsmeow com dados do device e a coluna que o identifica,
+// em ordem de dependencia (o device primeiro, por causa das FKs)
+var deviceTables = []struct {
+	name   string
+	column string
+}{
+	{"whatsmeow_device", "jid"},
+	{"whatsmeow_identity_keys", "our_jid"},
+	{"whatsmeow_pre_keys", "jid"},
+	{"whatsmeow_sessions", "our_jid"},
+	{"whatsmeow_sender_keys", "our_jid"},
+	{"whatsmeow_app_state_sync_keys", "jid"},
+	{"whatsmeow_app_state_version", "jid"},
+	{"whatsmeow_app_state_mutation_macs", "jid"},
+	{"whatsmeow_contacts", "our_jid"},
+	{"whatsmeow_chat_settings", "our_jid"},
+	{"whatsmeow_message_secrets", "our_jid"},
+	{"whatsmeow_privacy_tokens", "our_jid"},
+	{"whatsmeow_event_buffer", "our_jid"},
+}
+
+// TransferRepository exporta e importa sessoes completas (linha do FioZap + dados do whatsmeow)
+type TransferRepository interface {
+	ExportDevice(ctx context.Context, jid string) (DeviceData, error)
+	ImportSession(ctx context.Context, session *SessionModel, device DeviceData) error
+}
+
+// transferRepository implementa TransferRepository usando PostgreSQL
+type transferRepository struct {
+	db *sql.DB
+}
+
+// NewTransferRepository cria um novo TransferRepository
+func NewTransferRepository(db *sql.DB) TransferRepository {
+	return &transferRepository{db: db}
+}
+
+// ExportDevice le as linhas do device em JSON (bytea vira texto hex, aceito de volta na importacao)
+func (r *transferRepository) ExportDevice(ctx context.Context, jid string) (DeviceData, error) {
+	data := make(DeviceData, len(deviceTables))
+	for _, t := range deviceTables {
+		rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`SELECT row_to_json(t)::text FROM %q t WHERE %q = $1`, t.name, t.column), jid)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", t.name, err)
+		}
+
+		var list []json.RawMessage
+		for rows.Next() {
+			var row string
+			if err := rows.Scan(&row); err != nil {
+				_ = rows.Close()
+				return nil, err
+			}
+			list = append(list, json.RawMessage(row))
+		}
+		err = rows.Err()
+		_ = rows.Close()
+		if err != nil {
+			return nil, err
+		}
+		data[t.name] = list
+	}
+
+	if len(data["whatsmeow_device"]) == 0 {
+		return nil, fmt.Errorf("device %s not found in store", jid)
+	}
+	return data, nil
+}
+
+// ImportSession grava os dados do device e a linha da sessao numa unica transacao
+func (r *transferRepository) ImportSession(ctx context.Context, session *SessionModel, device DeviceData) error {
+	metadata, tags, err := marshalSessionLabels(session)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, t := range deviceTables {
+		for _, row := range device[t.name] {
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %q SELECT * FROM json_populate_record(NULL::%q, $1::json)`, t.name, t.name), string(row)); err != nil {
+				return fmt.Errorf("failed to import %s: %w", t.name, err)
+			}
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO "sessions" ("id", "name", "tenantId", "proxy", "metadata", "tags", "deviceName", "devicePlatform", "idleTimeout", "tokenHash", "tokenExpiresAt", "jid", "phone", "pushName", "connected", "status")
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	`, session.ID, session.Name, session.TenantID, session.Proxy, metadata, tags, session.DeviceName, session.DevicePlatform, session.IdleTimeout, session.TokenHash, session.TokenExpiresAt, session.JID, session.Phone, session.PushName, session.Connected, session.Status); err != nil {
+		return fmt.Errorf("failed to import session: %w", err)
+	}
+
+	return tx.Commit()
+}